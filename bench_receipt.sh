@@ -1,14 +1,9 @@
 #!/bin/bash
 
-txcount=10
+txcount=30
 plot_dir="plots"
 name=$1
 
 cp $name.env .env
-echo "Benchmarking Arbitrum..."
-echo "interval=10ms"
-go run . bench receiptcount --txcount $txcount --plot-prefix "$name-count-receipt-10ms" --plot-dir=$plot_dir --plot
-echo "interval=50ms"
-go run . bench receiptcount --txcount $txcount --plot-prefix "$name-count-receipt-50ms" --plot-dir=$plot_dir --plot
-echo "interval=100ms"
-go run . bench receiptcount --txcount $txcount --plot-prefix "$name-count-receipt-100ms" --plot-dir=$plot_dir --plot
+echo "Benchmarking $name receipt polling..."
+go run . bench polling --txcount $txcount --strategies "fixed:10ms,fixed:50ms,fixed:100ms,backoff:5ms:2:500ms,jitter:50ms:0.5,blocktime" --plot-prefix "$name-polling" --plot-dir=$plot_dir --plot
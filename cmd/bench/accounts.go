@@ -0,0 +1,102 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+// accountsFlags are shared by the accounts subcommands.
+var accountsFlags = append([]cli.Flag{
+	&cli.StringFlag{
+		Name:  "env-file",
+		Usage: "Path to .env file with RPC_ENDPOINT and PRIVATE_KEYS; the first key is the funder",
+		Value: ".env",
+	},
+	&cli.IntFlag{
+		Name:  "count",
+		Usage: "Number of ephemeral accounts derived from the funder key",
+		Value: 100,
+	},
+	&cli.IntFlag{
+		Name:  "batch-size",
+		Usage: "Number of transfers sent in one JSON-RPC batch",
+		Value: 50,
+	},
+	&cli.DurationFlag{
+		Name:  "poll-interval",
+		Usage: "Delay between receipt polling rounds",
+		Value: 100 * time.Millisecond,
+	},
+	&cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "How long to wait for the receipts of a batch",
+		Value: 2 * time.Minute,
+	},
+}, LogFlags...)
+
+var AccountsCommand = &cli.Command{
+	Name:  "accounts",
+	Usage: "Manage ephemeral sender accounts derived from the funder key (use them with EPHEMERAL_ACCOUNTS)",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "fund",
+			Usage: "Top up ephemeral accounts to --amount ETH from the funder",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:     "amount",
+					Usage:    "ETH balance each ephemeral account is topped up to, e.g. 0.01",
+					Required: true,
+				},
+			}, accountsFlags...),
+			Action: func(c *cli.Context) error {
+				flushLogs, err := setupLogging(c)
+				if err != nil {
+					return err
+				}
+				defer flushLogs()
+
+				if err := bench.LoadEnv(c.String("env-file")); err != nil {
+					return fmt.Errorf("failed to load env: %w", err)
+				}
+				amount, err := bench.ParseEther(c.String("amount"))
+				if err != nil {
+					return err
+				}
+
+				result, err := bench.FundAccounts(c.Int("count"), amount, c.Int("batch-size"), c.Duration("poll-interval"), c.Duration("timeout"))
+				// A partial run is still reported, as its transfers have been sent.
+				if err == nil || result.Txs > 0 || len(result.Failed) > 0 {
+					bench.PrintAccountsReport("Funded", result)
+				}
+				return err
+			},
+		},
+		{
+			Name:  "sweep",
+			Usage: "Return the remaining balance of ephemeral accounts to the funder",
+			Flags: accountsFlags,
+			Action: func(c *cli.Context) error {
+				flushLogs, err := setupLogging(c)
+				if err != nil {
+					return err
+				}
+				defer flushLogs()
+
+				if err := bench.LoadEnv(c.String("env-file")); err != nil {
+					return fmt.Errorf("failed to load env: %w", err)
+				}
+
+				result, err := bench.SweepAccounts(c.Int("count"), c.Int("batch-size"), c.Duration("poll-interval"), c.Duration("timeout"))
+				// A partial run is still reported, as its transfers have been sent.
+				if err == nil || result.Txs > 0 || len(result.Failed) > 0 {
+					bench.PrintAccountsReport("Swept", result)
+				}
+				return err
+			},
+		},
+	},
+}
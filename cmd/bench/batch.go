@@ -0,0 +1,62 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var BatchCommand = &cli.Command{
+	Name:  "batch",
+	Usage: "Compare JSON-RPC batch requests with one request per call for sending txs and polling receipts",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Path to .env file with RPC_ENDPOINT and PRIVATE_KEYS",
+			Value: ".env",
+		},
+		&cli.IntFlag{
+			Name:    "txcount",
+			Aliases: []string{"n"},
+			Usage:   "Number of transactions to send in each mode",
+			Value:   50,
+		},
+		&cli.IntFlag{
+			Name:  "batch-size",
+			Usage: "Number of transactions sent and polled together",
+			Value: 10,
+		},
+		&cli.DurationFlag{
+			Name:  "poll-interval",
+			Usage: "Delay between receipt polling rounds",
+			Value: 10 * time.Millisecond,
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "How long to wait for the receipts of a group",
+			Value: 60 * time.Second,
+		},
+	}, LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if err := bench.LoadEnv(c.String("env-file")); err != nil {
+			return fmt.Errorf("failed to load env: %w", err)
+		}
+
+		results, err := bench.RunBatchBenchmark(c.Int("txcount"), c.Int("batch-size"), c.Duration("poll-interval"), c.Duration("timeout"))
+		if err != nil {
+			return err
+		}
+
+		bench.PrintBatchReport(results)
+		return nil
+	},
+}
@@ -1,16 +1,22 @@
 package bench
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/iancoleman/strcase"
 	"github.com/urfave/cli/v2"
+	"io"
+	"os"
 	"path/filepath"
+	"slices"
 	"time"
 )
 
-var BenchFlags = []cli.Flag{
+// TxFlags select how many benchmark transactions are sent and where their plots go.
+var TxFlags = []cli.Flag{
 	&cli.IntFlag{
 		Name:    "txcount",
 		Aliases: []string{"n"},
@@ -28,11 +34,6 @@ var BenchFlags = []cli.Flag{
 		Usage: "Path to .env file with RPC_ENDPOINT and PRIVATE_KEYS",
 		Value: ".env",
 	},
-	&cli.StringFlag{
-		Name:  "mode",
-		Usage: "Transaction submission mode: 'async' or 'sync'",
-		Value: "async",
-	},
 	&cli.BoolFlag{
 		Name:  "plot",
 		Usage: "Generate PNG plots for the benchmark results",
@@ -50,12 +51,366 @@ var BenchFlags = []cli.Flag{
 	},
 }
 
+// RunFlags configure a run of the async or sync runner and are shared by bench and bench compare.
+var RunFlags = slices.Concat(TxFlags, []cli.Flag{
+	&cli.IntFlag{
+		Name:  "warmup",
+		Usage: "Number of transactions to send before the measured ones, left out of the statistics",
+		Value: 0,
+	},
+	&cli.DurationFlag{
+		Name:  "settle",
+		Usage: "How long to wait after connecting before sending the first transaction (if unset, 10s in sync mode and none in async mode)",
+	},
+	&cli.BoolFlag{
+		Name:  "plot-log",
+		Usage: "Use a logarithmic latency axis for CDF plots",
+		Value: false,
+	},
+	&cli.StringFlag{
+		Name:    "otlp-endpoint",
+		Usage:   "OTLP/HTTP collector host:port to export per-transaction traces to (e.g. localhost:4318)",
+		EnvVars: []string{"OTLP_ENDPOINT"},
+	},
+	&cli.StringFlag{
+		Name:  "trace-file",
+		Usage: "File to write per-transaction traces to as JSON",
+	},
+	&cli.StringFlag{
+		Name:  "max-spend",
+		Usage: "Stop before the fees paid could exceed this many ETH, e.g. 0.05",
+	},
+	&cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Run the preflight checks, build and sign every tx and print the plan without sending anything",
+		Value: false,
+	},
+	&cli.DurationFlag{
+		Name:  "stuck-timeout",
+		Usage: "Act on a tx without a receipt after this long, using --stuck-action (only async mode, 0 to wait forever)",
+		Value: 0,
+	},
+	&cli.StringFlag{
+		Name:  "stuck-action",
+		Usage: "What to do with a stuck tx: 'rebroadcast', 'bump' (replace with 12% higher fees) or 'cancel' (zero-value self-transfer)",
+		Value: "bump",
+	},
+	&cli.IntFlag{
+		Name:  "stuck-max-attempts",
+		Usage: "Number of times to act on a stuck tx before aborting the run",
+		Value: 3,
+	},
+	&cli.BoolFlag{
+		Name:  "ui",
+		Usage: "Show a live terminal dashboard during the run (logs are written to --log-file)",
+		Value: false,
+	},
+}, LogFlags)
+
+// BenchFlags are the flags of a single-mode bench run: RunFlags plus reporting, export and gating.
+var BenchFlags = slices.Concat([]cli.Flag{
+	&cli.StringFlag{
+		Name:  "mode",
+		Usage: "Transaction submission mode: 'async' or 'sync'",
+		Value: "async",
+	},
+	&cli.StringFlag{
+		Name:  "outliers",
+		Usage: "Report outliers separately: 'none', 'iqr' or 'mad' (outliers stay in the main statistics)",
+		Value: "none",
+	},
+	&cli.Float64Flag{
+		Name:  "outlier-threshold",
+		Usage: "Outlier threshold k: IQRs beyond the quartiles for 'iqr', modified z-score for 'mad' (0 for 1.5 and 3.5)",
+		Value: 0,
+	},
+	&cli.StringFlag{
+		Name:  "outlier-metric",
+		Usage: "Metric to detect outliers on: 'send', 'confirm' or 'total'",
+		Value: "total",
+	},
+	&cli.StringFlag{
+		Name:  "output",
+		Usage: "File to save the per-transaction results to as JSON, for 'bench diff'",
+	},
+	&cli.StringFlag{
+		Name:  "baseline",
+		Usage: "Results file saved with --output to evaluate --fail-if assertions against",
+	},
+	&cli.StringSliceFlag{
+		Name:  "fail-if",
+		Usage: "Exit non-zero if the run matches this condition, e.g. \"p99 > +15%\", \"confirm.median > +200ms\" or \"max > 3000ms\" (repeatable)",
+	},
+	&cli.BoolFlag{
+		Name:  "update-baseline",
+		Usage: "Save this run as the new --baseline if no assertion failed",
+		Value: false,
+	},
+	&cli.IntFlag{
+		Name:  "clock-samples",
+		Usage: "Number of new heads observed to estimate the local clock offset against block timestamps (0 to disable)",
+		Value: 3,
+	},
+	&cli.BoolFlag{
+		Name:  "track-levels",
+		Usage: "Also poll for the pending and preconfirmed levels while waiting for receipts (only async mode)",
+		Value: false,
+	},
+	&cli.DurationFlag{
+		Name:  "finality-timeout",
+		Usage: "Track each tx until its block is safe and finalized, waiting up to this long after the last send (only async mode, 0 to disable)",
+		Value: 0,
+	},
+	&cli.DurationFlag{
+		Name:  "finality-poll-interval",
+		Usage: "Polling interval for the safe and finalized heads",
+		Value: time.Second,
+	},
+	&cli.Uint64Flag{
+		Name:  "reorg-depth",
+		Usage: "Re-verify each tx's inclusion block until it is this many blocks deep (only async mode, 0 to disable)",
+		Value: 0,
+	},
+	&cli.DurationFlag{
+		Name:  "reorg-poll-interval",
+		Usage: "Polling interval for new heads while monitoring reorgs",
+		Value: 200 * time.Millisecond,
+	},
+	&cli.DurationFlag{
+		Name:  "reorg-timeout",
+		Usage: "How long to wait after the last send for txs to reach --reorg-depth",
+		Value: 2 * time.Minute,
+	},
+}, RunFlags)
+
+var LogFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "log-level",
+		Usage:   "Minimum log level: 'debug', 'info', 'warn' or 'error'",
+		Value:   "info",
+		EnvVars: []string{"LOG_LEVEL"},
+	},
+	&cli.StringFlag{
+		Name:  "log-format",
+		Usage: "Log output format: 'text' or 'json'",
+		Value: "text",
+	},
+	&cli.StringFlag{
+		Name:  "log-file",
+		Usage: "File to write logs to instead of stderr (defaults to bench.log when --ui is enabled)",
+	},
+}
+
+// setupLogging configures the shared logger from the CLI flags. The returned function flushes pending records.
+func setupLogging(c *cli.Context) (func(), error) {
+	logPath := c.String("log-file")
+	if logPath == "" && c.Bool("ui") {
+		logPath = "bench.log"
+	}
+
+	var out io.Writer = os.Stderr
+	var logFile *os.File
+	if logPath != "" {
+		var err error
+		logFile, err = os.Create(logPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create log file: %w", err)
+		}
+		out = logFile
+	}
+
+	flush, err := bench.SetupLogger(out, c.String("log-level"), c.String("log-format"))
+	if err != nil {
+		if logFile != nil {
+			logFile.Close()
+		}
+		return nil, err
+	}
+
+	return func() {
+		if err := flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to flush logs: %v\n", err)
+		}
+		if logFile != nil {
+			logFile.Close()
+			fmt.Printf("Logs written to '%s'\n", logPath)
+		}
+	}, nil
+}
+
+// startUI starts the live dashboard for a run of total transactions if --ui is set.
+// The returned function stops the dashboard.
+func startUI(c *cli.Context, total int) func() {
+	if !c.Bool("ui") {
+		return func() {}
+	}
+
+	dashboard := bench.NewDashboard(total, os.Stdout)
+	bench.SetObserver(dashboard)
+	dashboard.Start()
+
+	return func() {
+		dashboard.Stop()
+		bench.SetObserver(nil)
+	}
+}
+
+// initTracing sets up trace export from the CLI flags. The returned function flushes pending spans.
+func initTracing(c *cli.Context) (func(), error) {
+	shutdown, err := bench.InitTracing(c.Context, c.String("otlp-endpoint"), c.String("trace-file"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to init tracing: %w", err)
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			bench.Logger().Warn("failed to flush traces", "err", err)
+		}
+	}, nil
+}
+
+// defaultSyncSettle is how long sync runs have always waited before their first transaction.
+const defaultSyncSettle = 10 * time.Second
+
+// settlePeriod returns --settle, or the default settle period of mode if it is not set.
+func settlePeriod(c *cli.Context, mode string) time.Duration {
+	if !c.IsSet("settle") && mode == "sync" {
+		return defaultSyncSettle
+	}
+	return c.Duration("settle")
+}
+
+// setSpendLimit applies --max-spend, if set.
+func setSpendLimit(c *cli.Context) error {
+	if !c.IsSet("max-spend") {
+		bench.SetSpendLimit(nil)
+		return nil
+	}
+	limit, err := bench.ParseEther(c.String("max-spend"))
+	if err != nil {
+		return fmt.Errorf("invalid --max-spend: %w", err)
+	}
+	bench.SetSpendLimit(limit)
+	return nil
+}
+
+// estimateClockOffset estimates the local clock offset from --clock-samples new heads, if enabled.
+func estimateClockOffset(c *cli.Context, client *ethclient.Client) (bench.ClockOffset, error) {
+	samples := c.Int("clock-samples")
+	if samples <= 0 {
+		return bench.ClockOffset{}, nil
+	}
+	bench.Logger().Info("estimating clock offset", "heads", samples)
+	offset, err := bench.EstimateClockOffset(c.Context, client.Client(), samples, 50*time.Millisecond)
+	if err != nil {
+		return bench.ClockOffset{}, fmt.Errorf("failed to estimate clock offset: %w", err)
+	}
+	bench.Logger().Info("clock offset estimated", "offset", offset.Offset, "heads", offset.Samples, "precise", offset.Precise)
+	return offset, nil
+}
+
+// savePlotDistributions saves the histogram, CDF and violin plots of results with the --plot-prefix.
+func savePlotDistributions(c *cli.Context, results []bench.Result, title string) {
+	prefix := filepath.Join(c.String("plot-dir"), c.String("plot-prefix"))
+
+	if err := bench.PlotLatencyHistograms(results, title, prefix+"_hist.png"); err != nil {
+		bench.Logger().Warn("failed to generate latency histograms", "err", err)
+	} else {
+		bench.Logger().Info("latency histograms saved", "path", prefix+"_hist.png")
+	}
+
+	if err := bench.PlotCDF(bench.ResultsCDFSeries(results), title+" Latency CDF", c.Bool("plot-log"), prefix+"_cdf.png"); err != nil {
+		bench.Logger().Warn("failed to generate latency CDF", "err", err)
+	} else {
+		bench.Logger().Info("latency CDF saved", "path", prefix+"_cdf.png")
+	}
+
+	if err := bench.PlotLatencyViolins(results, title+" Latency Distribution", prefix+"_violin.png"); err != nil {
+		bench.Logger().Warn("failed to generate latency violin plot", "err", err)
+	} else {
+		bench.Logger().Info("latency violin plot saved", "path", prefix+"_violin.png")
+	}
+}
+
+// loadGate parses the --fail-if assertions and loads the --baseline run they compare against. A missing
+// baseline file is only accepted with --update-baseline, which creates it.
+func loadGate(c *cli.Context) ([]bench.Assertion, []bench.Result, error) {
+	var assertions []bench.Assertion
+	needsBaseline := false
+	for _, s := range c.StringSlice("fail-if") {
+		a, err := bench.ParseAssertion(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		assertions = append(assertions, a)
+		needsBaseline = needsBaseline || a.NeedsBaseline()
+	}
+
+	path := c.String("baseline")
+	if path == "" {
+		if needsBaseline || c.Bool("update-baseline") {
+			return nil, nil, fmt.Errorf("--baseline is required for relative --fail-if assertions and --update-baseline")
+		}
+		return assertions, nil, nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && c.Bool("update-baseline") {
+		bench.Logger().Warn("baseline does not exist yet, skipping relative assertions", "path", path)
+		var absolute []bench.Assertion
+		for _, a := range assertions {
+			if !a.NeedsBaseline() {
+				absolute = append(absolute, a)
+			}
+		}
+		return absolute, nil, nil
+	}
+	run, err := bench.LoadRunFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load baseline: %w", err)
+	}
+	return assertions, run.Results, nil
+}
+
+// checkGate evaluates the assertions on run, updates the baseline if requested and everything passed,
+// and returns an error if any assertion failed so the process exits non-zero.
+func checkGate(c *cli.Context, assertions []bench.Assertion, baseline []bench.Result, run bench.RunFile) error {
+	evaluated, err := bench.EvaluateAssertions(assertions, run.Results, baseline)
+	if err != nil {
+		return err
+	}
+	bench.PrintGateReport(evaluated)
+	failed := bench.GateFailures(evaluated)
+
+	if c.Bool("update-baseline") {
+		if failed > 0 {
+			bench.Logger().Warn("not updating the baseline of a failing run", "path", c.String("baseline"))
+		} else if len(run.Results) == 0 {
+			bench.Logger().Warn("not updating the baseline with a run without measured txs", "path", c.String("baseline"))
+		} else if err := bench.SaveRunFile(c.String("baseline"), run); err != nil {
+			return err
+		} else {
+			bench.Logger().Info("baseline updated", "path", c.String("baseline"))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("regression gate failed: %d of %d assertions failed", failed, len(evaluated))
+	}
+	return nil
+}
+
 var BenchCommand = &cli.Command{
 	Name:        "bench",
 	Usage:       "Benchmark EVM transaction submission and receipt latency",
 	Flags:       BenchFlags,
-	Subcommands: []*cli.Command{CompareSubcommand, ReceiptCountCommand, BlockNumberCommand},
+	Subcommands: []*cli.Command{CompareSubcommand, ReceiptCountCommand, BlockNumberCommand, PropagationCommand, BroadcastCommand, RPCCommand, LoadCommand, BatchCommand, PollingCommand, BlocksCommand, AccountsCommand, FeesCommand, DiffCommand, PlotCommand},
 	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
 		envFile := c.String("env-file")
 		if err := bench.LoadEnv(envFile); err != nil {
 			return err
@@ -68,11 +423,33 @@ var BenchCommand = &cli.Command{
 		plotPrefix := c.String("plot-prefix")
 		plotDir := c.String("plot-dir")
 
+		outlierMethod, err := bench.ParseOutlierMethod(c.String("outliers"))
+		if err != nil {
+			return err
+		}
+		outlierMetric, err := bench.ParseMetric(c.String("outlier-metric"))
+		if err != nil {
+			return err
+		}
+		outlierThreshold := c.Float64("outlier-threshold")
+		if outlierThreshold <= 0 {
+			outlierThreshold = bench.DefaultOutlierThreshold(outlierMethod)
+		}
+
+		assertions, baseline, err := loadGate(c)
+		if err != nil {
+			return err
+		}
+
+		flushTraces, err := initTracing(c)
+		if err != nil {
+			return err
+		}
+		defer flushTraces()
+
 		var results []bench.Result
-		var err error
 
-		fmt.Println("Extracting RPC response time metrics...")
-		fmt.Printf("RPCEndpoint: %v\n", bench.RPCEndpoint())
+		bench.Logger().Info("extracting RPC response time metrics", "endpoint", bench.RPCEndpoint())
 		client, err := ethclient.Dial(bench.RPCEndpoint())
 		if err != nil {
 			return fmt.Errorf("failed to connect RPC endpoint: %w", err)
@@ -89,29 +466,97 @@ var BenchCommand = &cli.Command{
 		fmt.Printf("Avg:    %v\n", metrics[2])
 		fmt.Printf("Median: %v\n", metrics[3])
 
+		clockOffset, err := estimateClockOffset(c, client)
+		if err != nil {
+			return err
+		}
+
+		if err := setSpendLimit(c); err != nil {
+			return err
+		}
+		estimate, err := bench.EstimateCost(c.Context, client, c.Int("warmup")+txCount)
+		if err != nil {
+			return err
+		}
+		bench.PrintCostEstimate(estimate)
+
+		bench.SetDryRun(c.Bool("dry-run"))
+		stuckAction, err := bench.ParseStuckAction(c.String("stuck-action"))
+		if err != nil {
+			return err
+		}
+		bench.SetStuckPolicy(bench.StuckPolicy{
+			Action:      stuckAction,
+			Timeout:     c.Duration("stuck-timeout"),
+			MaxAttempts: c.Int("stuck-max-attempts"),
+		})
+		bench.SetLevelTracking(c.Bool("track-levels"))
+		bench.SetFinalityTracking(c.Duration("finality-poll-interval"), c.Duration("finality-timeout"))
+		bench.SetReorgMonitoring(c.Uint64("reorg-depth"), c.Duration("reorg-poll-interval"), c.Duration("reorg-timeout"))
+		bench.SetWarmup(c.Int("warmup"))
+		bench.SetSettlePeriod(settlePeriod(c, mode))
+		stopUI := startUI(c, c.Int("warmup")+txCount)
+
+		startedAt := time.Now()
 		switch mode {
 		case "async":
 			results, err = bench.RunBenchmarkAsync(txCount, pollInterval)
 		case "sync":
 			results, err = bench.RunBenchmarkSync(txCount)
 		default:
-			return fmt.Errorf("invalid mode: %s, must be 'async' or 'sync'", mode)
+			err = fmt.Errorf("invalid mode: %s, must be 'async' or 'sync'", mode)
 		}
+		stopUI()
 		if err != nil {
 			return err
 		}
-
+		if c.Bool("dry-run") {
+			return nil
+		}
+		all := results
+		if excluded := len(all) - len(bench.MeasuredResults(all)); excluded > 0 {
+			bench.Logger().Info("excluding warm-up and cancelled txs from the statistics", "txs", excluded)
+			results = bench.MeasuredResults(all)
+		}
 		bench.PrintReport(results)
+		bench.PrintOutlierReport(bench.DetectOutliers(results, outlierMetric, outlierMethod, outlierThreshold), results)
+		bench.PrintLevelsReport(results)
+		bench.PrintStuckReport(all)
+		bench.PrintReorgReport(results)
+		// Warm-up and cancelled txs were paid for too, so the cost covers every tx sent.
+		bench.PrintCostReport(all)
+		if c.Int("clock-samples") > 0 {
+			bench.CorrectClockSkew(results, clockOffset)
+			bench.PrintClockReport(results, clockOffset)
+		}
+
+		// The export and the baseline are written from the same, fully post-processed run.
+		run := bench.NewRunFile(mode, startedAt, results)
+		if output := c.String("output"); output != "" {
+			if err := bench.SaveRunFile(output, run); err != nil {
+				return err
+			}
+			bench.Logger().Info("results saved", "path", output)
+		}
 
 		if plotEnabled {
 			fullPath := filepath.Join(plotDir, plotPrefix+".png")
 			if err := bench.PlotCombinedMetrics(results, metrics[3], strcase.ToCamel(mode), fullPath); err != nil {
-				fmt.Printf("Warning: failed to generate combined plot: %v\n", err)
+				bench.Logger().Warn("failed to generate combined plot", "err", err)
 			} else {
-				fmt.Printf("Combined benchmark plot saved as '%s'\n", fullPath)
+				bench.Logger().Info("combined benchmark plot saved", "path", fullPath)
 			}
+
+			levelsPath := filepath.Join(plotDir, plotPrefix+"_levels.png")
+			if err := bench.PlotConfirmationLevels(results, strcase.ToCamel(mode)+" Confirmation Levels", levelsPath); err != nil {
+				bench.Logger().Warn("failed to generate confirmation levels plot", "err", err)
+			} else {
+				bench.Logger().Info("confirmation levels plot saved", "path", levelsPath)
+			}
+
+			savePlotDistributions(c, results, strcase.ToCamel(mode))
 		}
 
-		return nil
+		return checkGate(c, assertions, baseline, run)
 	},
 }
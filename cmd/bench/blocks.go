@@ -0,0 +1,77 @@
+package bench
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var BlocksCommand = &cli.Command{
+	Name:  "blocks",
+	Usage: "Analyze block cadence, gas usage and header timestamp drift",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Path to .env file with RPC_ENDPOINT and PRIVATE_KEYS",
+			Value: ".env",
+		},
+		&cli.DurationFlag{
+			Name:  "duration",
+			Usage: "How long to observe new blocks",
+			Value: 2 * time.Minute,
+		},
+		&cli.DurationFlag{
+			Name:  "poll-interval",
+			Usage: "Interval between eth_blockNumber polls (ignored for WebSocket endpoints, which subscribe to new heads)",
+			Value: 50 * time.Millisecond,
+		},
+		&cli.BoolFlag{
+			Name:  "plot",
+			Usage: "Generate a PNG histogram of block intervals",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "plot-prefix",
+			Usage: "Filename prefix for output PNG plots",
+			Value: "blocks",
+		},
+		&cli.StringFlag{
+			Name:  "plot-dir",
+			Usage: "Directory to save PNG plot files",
+			Value: ".",
+		},
+	}, LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if err := bench.LoadEnv(c.String("env-file")); err != nil {
+			return fmt.Errorf("failed to load env: %w", err)
+		}
+
+		bench.Logger().Info("observing blocks", "duration", c.Duration("duration"))
+		blocks, err := bench.ObserveBlocks(c.Context, c.Duration("duration"), c.Duration("poll-interval"))
+		if err != nil {
+			return err
+		}
+
+		bench.PrintBlocksReport(blocks)
+
+		if c.Bool("plot") {
+			plotFile := filepath.Join(c.String("plot-dir"), c.String("plot-prefix")+"_intervals.png")
+			if err := bench.PlotBlockIntervalHistogram(blocks, plotFile); err != nil {
+				bench.Logger().Warn("failed to generate block interval histogram", "err", err)
+			} else {
+				bench.Logger().Info("block interval histogram saved", "path", plotFile)
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,68 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var BroadcastCommand = &cli.Command{
+	Name:  "broadcast",
+	Usage: "Send each signed transaction to several endpoints at once and measure which path is fastest",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Path to .env file with RPC_ENDPOINT, PRIVATE_KEYS and optionally EXTRA_RPC_ENDPOINTS",
+			Value: ".env",
+		},
+		&cli.IntFlag{
+			Name:    "txcount",
+			Aliases: []string{"n"},
+			Usage:   "Number of transactions to send sequentially",
+			Value:   10,
+		},
+		&cli.StringFlag{
+			Name:  "endpoints",
+			Usage: "Comma-separated RPC endpoints to broadcast to (defaults to RPC_ENDPOINT and EXTRA_RPC_ENDPOINTS)",
+		},
+		&cli.DurationFlag{
+			Name:  "poll-interval",
+			Usage: "Polling interval for receipt queries on each endpoint",
+			Value: 10 * time.Millisecond,
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "How long to wait for every endpoint to return a receipt before moving on",
+			Value: 60 * time.Second,
+		},
+	}, LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if err := bench.LoadEnv(c.String("env-file")); err != nil {
+			return fmt.Errorf("failed to load env: %w", err)
+		}
+
+		endpoints := bench.SplitList(c.String("endpoints"))
+		if len(endpoints) == 0 {
+			endpoints = append([]string{bench.RPCEndpoint()}, bench.ExtraRPCEndpoints()...)
+		}
+		if len(endpoints) < 2 {
+			return fmt.Errorf("broadcast needs at least two endpoints: set --endpoints or EXTRA_RPC_ENDPOINTS")
+		}
+
+		// A failed run still reports the txs broadcast before the failure.
+		results, err := bench.RunBroadcast(c.Int("txcount"), endpoints, c.Duration("poll-interval"), c.Duration("timeout"))
+		if len(results) > 0 {
+			bench.PrintBroadcastReport(results)
+		}
+		return err
+	},
+}
@@ -1,7 +1,6 @@
 package bench
 
 import (
-	"context"
 	"fmt"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
@@ -15,8 +14,14 @@ import (
 var CompareSubcommand = &cli.Command{
 	Name:  "compare",
 	Usage: "Compare benchmark results between async and sync modes",
-	Flags: BenchFlags,
+	Flags: RunFlags,
 	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
 		envFile := c.String("env-file")
 		if err := bench.LoadEnv(envFile); err != nil {
 			return err
@@ -28,8 +33,13 @@ var CompareSubcommand = &cli.Command{
 		plotDir := c.String("plot-dir")
 		plotPrefix := c.String("plot-prefix")
 
-		fmt.Println("Extracting RPC response time metrics...")
-		fmt.Printf("RPCEndpoint: %v\n", bench.RPCEndpoint())
+		flushTraces, err := initTracing(c)
+		if err != nil {
+			return err
+		}
+		defer flushTraces()
+
+		bench.Logger().Info("extracting RPC response time metrics", "endpoint", bench.RPCEndpoint())
 		client, err := ethclient.Dial(bench.RPCEndpoint())
 		if err != nil {
 			return fmt.Errorf("failed to connect RPC endpoint: %w", err)
@@ -46,22 +56,57 @@ var CompareSubcommand = &cli.Command{
 		fmt.Printf("Avg:    %v\n", metrics[2])
 		fmt.Printf("Median: %v\n", metrics[3])
 
-		fmt.Println("Running async benchmark...")
+		if err := setSpendLimit(c); err != nil {
+			return err
+		}
+		estimate, err := bench.EstimateCost(c.Context, client, 2*(c.Int("warmup")+txCount))
+		if err != nil {
+			return err
+		}
+		bench.PrintCostEstimate(estimate)
+
+		bench.SetDryRun(c.Bool("dry-run"))
+		stuckAction, err := bench.ParseStuckAction(c.String("stuck-action"))
+		if err != nil {
+			return err
+		}
+		bench.SetStuckPolicy(bench.StuckPolicy{
+			Action:      stuckAction,
+			Timeout:     c.Duration("stuck-timeout"),
+			MaxAttempts: c.Int("stuck-max-attempts"),
+		})
+		bench.SetWarmup(c.Int("warmup"))
+		bench.SetSettlePeriod(settlePeriod(c, "async"))
+		stopUI := startUI(c, 2*(c.Int("warmup")+txCount))
+
+		bench.Logger().Info("running async benchmark")
 		asyncResults, err := bench.RunBenchmarkAsync(txCount, pollInterval)
 		if err != nil {
+			stopUI()
 			return fmt.Errorf("async benchmark failed: %w", err)
 		}
 
-		fmt.Println("Running sync benchmark...")
+		bench.Logger().Info("running sync benchmark")
+		bench.SetSettlePeriod(settlePeriod(c, "sync"))
 		syncResults, err := bench.RunBenchmarkSync(txCount)
+		stopUI()
 		if err != nil {
 			return fmt.Errorf("sync benchmark failed: %w", err)
 		}
+		if c.Bool("dry-run") {
+			return nil
+		}
+		asyncResults = bench.MeasuredResults(asyncResults)
+		syncResults = bench.MeasuredResults(syncResults)
+		if len(asyncResults) == 0 || len(syncResults) == 0 {
+			return fmt.Errorf("no measured txs to compare: async %d, sync %d", len(asyncResults), len(syncResults))
+		}
 
 		// Print side-by-side total time table
 		fmt.Println("\nSide-by-Side Total Time Comparison (ms):")
 		fmt.Printf("%-6s %-15s %-15s\n", "TX#", "Async Total", "Sync Total")
-		for i := 0; i < txCount; i++ {
+		// A run stopped early by --max-spend has fewer results than txCount.
+		for i := 0; i < min(len(asyncResults), len(syncResults)); i++ {
 			fmt.Printf("%-6d %-15d %-15d\n",
 				i+1,
 				asyncResults[i].TotalTime,
@@ -95,33 +140,23 @@ var CompareSubcommand = &cli.Command{
 		if plotEnabled {
 			fullPath := filepath.Join(plotDir, plotPrefix+".png")
 			if err := bench.PlotWithBlockNumberBaseline(asyncResults, syncResults, metrics[3], fullPath); err != nil {
-				fmt.Printf("Warning: failed to generate combined plot: %v\n", err)
+				bench.Logger().Warn("failed to generate combined plot", "err", err)
 			} else {
-				fmt.Printf("Combined benchmark plot saved as '%s'\n", fullPath)
+				bench.Logger().Info("combined benchmark plot saved", "path", fullPath)
+			}
+
+			cdfPath := filepath.Join(plotDir, plotPrefix+"_cdf.png")
+			series := []bench.CDFSeries{
+				{Name: "Async Total Time", Values: bench.MetricValues(asyncResults, bench.MetricTotal)},
+				{Name: "Sync Total Time", Values: bench.MetricValues(syncResults, bench.MetricTotal)},
+			}
+			if err := bench.PlotCDF(series, "Total Time CDF", c.Bool("plot-log"), cdfPath); err != nil {
+				bench.Logger().Warn("failed to generate CDF plot", "err", err)
+			} else {
+				bench.Logger().Info("CDF plot saved", "path", cdfPath)
 			}
 		}
 
 		return nil
 	},
 }
-
-func MeasureBlockNumberMedian(ctx context.Context, client *ethclient.Client, calls int, interval time.Duration) (time.Duration, error) {
-	times := make([]time.Duration, 0, calls)
-	for i := 0; i < calls; i++ {
-		start := time.Now()
-		_, err := client.BlockNumber(ctx)
-		if err != nil {
-			return 0, err
-		}
-		elapsed := time.Since(start)
-		times = append(times, elapsed)
-		time.Sleep(interval)
-	}
-	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
-
-	mid := len(times) / 2
-	if len(times)%2 == 0 {
-		return (times[mid-1] + times[mid]) / 2, nil
-	}
-	return times[mid], nil
-}
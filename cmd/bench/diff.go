@@ -0,0 +1,53 @@
+package bench
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var DiffCommand = &cli.Command{
+	Name:      "diff",
+	Usage:     "Compare two runs saved with --output and test whether latency changed significantly",
+	ArgsUsage: "run_a.json run_b.json",
+	Flags: append([]cli.Flag{
+		&cli.Float64Flag{
+			Name:  "confidence",
+			Usage: "Confidence level of the significance test and bootstrap interval",
+			Value: 0.95,
+		},
+		&cli.IntFlag{
+			Name:  "bootstrap",
+			Usage: "Number of bootstrap resamples for the confidence interval of the median difference",
+			Value: 10000,
+		},
+	}, LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if c.NArg() != 2 {
+			return fmt.Errorf("expected two results files, got %d arguments", c.NArg())
+		}
+		a, err := bench.LoadRunFile(c.Args().Get(0))
+		if err != nil {
+			return err
+		}
+		b, err := bench.LoadRunFile(c.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		diffs, err := bench.DiffRuns(a.Results, b.Results, c.Float64("confidence"), c.Int("bootstrap"))
+		if err != nil {
+			return err
+		}
+		bench.PrintDiffReport(a, b, diffs)
+		return nil
+	},
+}
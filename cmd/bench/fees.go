@@ -0,0 +1,102 @@
+package bench
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var FeesCommand = &cli.Command{
+	Name:  "fees",
+	Usage: "Compare inclusion latency across priority fee levels",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Path to .env file with RPC_ENDPOINT and PRIVATE_KEYS",
+			Value: ".env",
+		},
+		&cli.IntFlag{
+			Name:    "txcount",
+			Aliases: []string{"n"},
+			Usage:   "Total number of transactions, shared between the tip levels",
+			Value:   40,
+		},
+		&cli.StringFlag{
+			Name:  "tips",
+			Usage: "Comma-separated tip levels as multiples of the suggested priority fee",
+			Value: "0,1x,2x,10x",
+		},
+		&cli.DurationFlag{
+			Name:  "poll-interval",
+			Usage: "Interval between receipt polls",
+			Value: 10 * time.Millisecond,
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "How long to wait for a tx before cancelling it and counting it as unconfirmed",
+			Value: time.Minute,
+		},
+		&cli.StringFlag{
+			Name:  "max-spend",
+			Usage: "Stop before the fees paid could exceed this many ETH, e.g. 0.05",
+		},
+		&cli.BoolFlag{
+			Name:  "plot",
+			Usage: "Generate a PNG box plot of latencies grouped by tip level",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "plot-prefix",
+			Usage: "Filename prefix for output PNG plots",
+			Value: "fees",
+		},
+		&cli.StringFlag{
+			Name:  "plot-dir",
+			Usage: "Directory to save PNG plot files",
+			Value: ".",
+		},
+	}, LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if err := bench.LoadEnv(c.String("env-file")); err != nil {
+			return fmt.Errorf("failed to load env: %w", err)
+		}
+
+		levels, err := bench.ParseTipLevels(c.String("tips"))
+		if err != nil {
+			return err
+		}
+
+		if err := setSpendLimit(c); err != nil {
+			return err
+		}
+
+		results, runErr := bench.RunFeeComparison(c.Int("txcount"), levels, c.Duration("poll-interval"), c.Duration("timeout"))
+		if len(results) == 0 {
+			return runErr
+		}
+
+		// A failed run still reports the txs sent before the failure.
+		summaries := bench.SummarizeFees(results, levels)
+		bench.PrintFeeReport(summaries)
+
+		if c.Bool("plot") {
+			plotFile := filepath.Join(c.String("plot-dir"), c.String("plot-prefix")+"_boxplot.png")
+			if err := bench.PlotFeeBoxes(summaries, plotFile); err != nil {
+				bench.Logger().Warn("failed to generate fee box plot", "err", err)
+			} else {
+				bench.Logger().Info("fee box plot saved", "path", plotFile)
+			}
+		}
+		return runErr
+	},
+}
@@ -0,0 +1,100 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var LoadCommand = &cli.Command{
+	Name:  "load",
+	Usage: "Ramp concurrent read load on the RPC endpoint to find the max sustainable QPS before rate limiting or latency inflation",
+	Flags: append(append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Path to .env file with RPC_ENDPOINT and PRIVATE_KEYS",
+			Value: ".env",
+		},
+		&cli.IntFlag{
+			Name:  "workers",
+			Usage: "Number of concurrent workers issuing calls",
+			Value: 16,
+		},
+		&cli.Float64Flag{
+			Name:  "qps-start",
+			Usage: "Target requests per second of the first step",
+			Value: 10,
+		},
+		&cli.Float64Flag{
+			Name:  "qps-step",
+			Usage: "Increase of the target rate between steps",
+			Value: 10,
+		},
+		&cli.Float64Flag{
+			Name:  "qps-max",
+			Usage: "Highest target rate to try",
+			Value: 200,
+		},
+		&cli.DurationFlag{
+			Name:  "step-duration",
+			Usage: "How long to hold each target rate",
+			Value: 10 * time.Second,
+		},
+		&cli.Float64Flag{
+			Name:  "max-error-rate",
+			Usage: "Share of failed calls (0-1) above which a step is unsustainable",
+			Value: 0.01,
+		},
+		&cli.Float64Flag{
+			Name:  "max-latency-inflation",
+			Usage: "Factor by which p90 latency may grow over the first step before a step is unsustainable",
+			Value: 2,
+		},
+	}, rpcMethodFlags...), LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if err := bench.LoadEnv(c.String("env-file")); err != nil {
+			return fmt.Errorf("failed to load env: %w", err)
+		}
+
+		client, err := ethclient.Dial(bench.RPCEndpoint())
+		if err != nil {
+			return fmt.Errorf("failed to connect RPC endpoint: %w", err)
+		}
+		defer client.Close()
+
+		method := c.String("method")
+		if method == "" {
+			method = "eth_blockNumber"
+		}
+		calls, err := resolveRPCCalls(c, client.Client(), method)
+		if err != nil {
+			return err
+		}
+
+		cfg := bench.LoadConfig{
+			Workers:             c.Int("workers"),
+			StartQPS:            c.Float64("qps-start"),
+			StepQPS:             c.Float64("qps-step"),
+			MaxQPS:              c.Float64("qps-max"),
+			StepDuration:        c.Duration("step-duration"),
+			MaxErrorRate:        c.Float64("max-error-rate"),
+			MaxLatencyInflation: c.Float64("max-latency-inflation"),
+		}
+		if cfg.Workers < 1 || cfg.StartQPS <= 0 || cfg.MaxQPS < cfg.StartQPS {
+			return fmt.Errorf("invalid load settings: need --workers >= 1 and 0 < --qps-start <= --qps-max")
+		}
+
+		bench.PrintLoadReport(bench.RunLoadTest(c.Context, client.Client(), calls[0], cfg))
+		return nil
+	},
+}
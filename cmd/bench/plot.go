@@ -0,0 +1,72 @@
+package bench
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var PlotCommand = &cli.Command{
+	Name:      "plot",
+	Usage:     "Overlay the latency CDFs of runs saved with --output, e.g. several modes or chains",
+	ArgsUsage: "[label=]run.json...",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "metric",
+			Usage: "Metric to plot: 'send', 'confirm' or 'total'",
+			Value: "total",
+		},
+		&cli.BoolFlag{
+			Name:  "log",
+			Usage: "Use a logarithmic latency axis",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "out",
+			Usage: "PNG file to write the CDF plot to",
+			Value: "cdf.png",
+		},
+	}, LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if c.NArg() == 0 {
+			return fmt.Errorf("expected at least one results file")
+		}
+		metric, err := bench.ParseMetric(c.String("metric"))
+		if err != nil {
+			return err
+		}
+
+		var series []bench.CDFSeries
+		for _, arg := range c.Args().Slice() {
+			// Files are labelled by their name unless a label is given as label=path.
+			label, path, ok := strings.Cut(arg, "=")
+			if !ok {
+				path = arg
+				label = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			}
+			run, err := bench.LoadRunFile(path)
+			if err != nil {
+				return err
+			}
+			series = append(series, bench.CDFSeries{Name: label, Values: bench.MetricValues(run.Results, metric)})
+		}
+
+		title := strcase.ToCamel(string(metric)) + " Time CDF"
+		if err := bench.PlotCDF(series, title, c.Bool("log"), c.String("out")); err != nil {
+			return err
+		}
+		bench.Logger().Info("CDF plot saved", "path", c.String("out"))
+		return nil
+	},
+}
@@ -0,0 +1,87 @@
+package bench
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var PollingCommand = &cli.Command{
+	Name:  "polling",
+	Usage: "Compare receipt polling strategies by detection latency and RPC calls spent",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Path to .env file with RPC_ENDPOINT and PRIVATE_KEYS",
+			Value: ".env",
+		},
+		&cli.IntFlag{
+			Name:    "txcount",
+			Aliases: []string{"n"},
+			Usage:   "Total number of transactions, shared between the strategies",
+			Value:   40,
+		},
+		&cli.StringFlag{
+			Name: "strategies",
+			Usage: "Comma-separated polling strategies: fixed:<interval>, backoff:<initial>[:<factor>[:<max>]], " +
+				"jitter:<interval>[:<fraction>], blocktime",
+			Value: "fixed:10ms,fixed:50ms,fixed:100ms,backoff:5ms:2:500ms,jitter:50ms:0.5,blocktime",
+		},
+		&cli.BoolFlag{
+			Name:  "plot",
+			Usage: "Generate a PNG Pareto chart of latency against RPC calls",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "plot-prefix",
+			Usage: "Filename prefix for output PNG plots",
+			Value: "polling",
+		},
+		&cli.StringFlag{
+			Name:  "plot-dir",
+			Usage: "Directory to save PNG plot files",
+			Value: ".",
+		},
+	}, LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if err := bench.LoadEnv(c.String("env-file")); err != nil {
+			return fmt.Errorf("failed to load env: %w", err)
+		}
+
+		var strategies []bench.PollStrategy
+		for _, spec := range bench.SplitList(c.String("strategies")) {
+			strategy, err := bench.ParsePollStrategy(spec)
+			if err != nil {
+				return err
+			}
+			strategies = append(strategies, strategy)
+		}
+
+		results, err := bench.RunPollingComparison(c.Int("txcount"), strategies)
+		if err != nil {
+			return err
+		}
+
+		summaries := bench.SummarizePolling(results)
+		bench.PrintPollingReport(summaries)
+
+		if c.Bool("plot") {
+			plotFile := filepath.Join(c.String("plot-dir"), c.String("plot-prefix")+"_pareto.png")
+			if err := bench.PlotPollingPareto(summaries, plotFile); err != nil {
+				bench.Logger().Warn("failed to generate Pareto plot", "err", err)
+			} else {
+				bench.Logger().Info("Pareto plot saved", "path", plotFile)
+			}
+		}
+		return nil
+	},
+}
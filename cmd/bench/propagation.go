@@ -0,0 +1,69 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+var PropagationCommand = &cli.Command{
+	Name:  "propagation",
+	Usage: "Send transactions via RPC_ENDPOINT and measure when other endpoints first expose them",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Path to .env file with RPC_ENDPOINT, PRIVATE_KEYS and optionally EXTRA_RPC_ENDPOINTS",
+			Value: ".env",
+		},
+		&cli.IntFlag{
+			Name:    "txcount",
+			Aliases: []string{"n"},
+			Usage:   "Number of transactions to send sequentially",
+			Value:   10,
+		},
+		&cli.StringFlag{
+			Name:  "endpoints",
+			Usage: "Comma-separated RPC endpoints to observe (defaults to EXTRA_RPC_ENDPOINTS)",
+		},
+		&cli.DurationFlag{
+			Name:  "poll-interval",
+			Usage: "Polling interval for transaction and receipt queries on each endpoint",
+			Value: 10 * time.Millisecond,
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "How long to wait for every endpoint to expose a transaction before moving on",
+			Value: 60 * time.Second,
+		},
+	}, LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if err := bench.LoadEnv(c.String("env-file")); err != nil {
+			return fmt.Errorf("failed to load env: %w", err)
+		}
+
+		endpoints := bench.SplitList(c.String("endpoints"))
+		if len(endpoints) == 0 {
+			endpoints = bench.ExtraRPCEndpoints()
+		}
+		if len(endpoints) == 0 {
+			return fmt.Errorf("no endpoints to observe: set --endpoints or EXTRA_RPC_ENDPOINTS")
+		}
+
+		results, err := bench.RunPropagation(c.Int("txcount"), endpoints, c.Duration("poll-interval"), c.Duration("timeout"))
+		if err != nil {
+			return err
+		}
+
+		bench.PrintPropagationReport(results)
+		return nil
+	},
+}
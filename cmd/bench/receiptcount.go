@@ -7,13 +7,12 @@ import (
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/plotutil"
 	"gonum.org/v1/plot/vg"
-	"log"
 	"math/big"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
 
@@ -23,8 +22,14 @@ import (
 var ReceiptCountCommand = &cli.Command{
 	Name:  "receiptcount",
 	Usage: "Send transactions and count eth_getTransactionReceipt calls per transaction",
-	Flags: BenchFlags,
+	Flags: slices.Concat(TxFlags, LogFlags),
 	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
 		if err := bench.LoadEnv(c.String("env-file")); err != nil {
 			return fmt.Errorf("failed to load env: %w", err)
 		}
@@ -43,17 +48,13 @@ var ReceiptCountCommand = &cli.Command{
 		plotPrefix := c.String("plot-prefix")
 		ctx := context.Background()
 
-		fmt.Printf("Sending %d transactions and counting receipt polling calls...\n", txCount)
+		bench.Logger().Info("sending transactions and counting receipt polling calls", "txcount", txCount)
 
 		receiptCallCounts := make([]int, 0, txCount)
 
 		for i := 0; i < txCount; i++ {
-			keyHex := bench.PrivKeys()[i%len(bench.PrivKeys())]
-			privKey, err := crypto.HexToECDSA(keyHex)
-			if err != nil {
-				return fmt.Errorf("invalid private key: %w", err)
-			}
-			fromAddress := crypto.PubkeyToAddress(privKey.PublicKey)
+			signer := bench.Signers()[i%len(bench.Signers())]
+			fromAddress := signer.Address()
 
 			nonce, err := client.PendingNonceAt(ctx, fromAddress)
 			if err != nil {
@@ -93,7 +94,7 @@ var ReceiptCountCommand = &cli.Command{
 
 			tx := types.NewTx(txData)
 
-			signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), privKey)
+			signedTx, err := signer.SignTx(ctx, tx, chainID)
 			if err != nil {
 				return fmt.Errorf("failed to sign transaction: %w", err)
 			}
@@ -104,7 +105,7 @@ var ReceiptCountCommand = &cli.Command{
 			}
 
 			txHash := signedTx.Hash()
-			log.Printf("[INFO] Tx %d sent: %s", i+1, txHash.Hex())
+			bench.Logger().Info("tx sent", "tx", i+1, "hash", txHash.Hex())
 
 			// Poll for receipt and count calls
 			receiptCallCount := 0
@@ -118,7 +119,7 @@ var ReceiptCountCommand = &cli.Command{
 				time.Sleep(pollInterval)
 			}
 
-			log.Printf("[INFO] Tx %d receipt obtained after %d eth_getTransactionReceipt calls", i+1, receiptCallCount)
+			bench.Logger().Debug("receipt obtained", "tx", i+1, "receipt_calls", receiptCallCount)
 			fmt.Printf("Tx %d: Receipt calls = %d\n", i+1, receiptCallCount)
 
 			receiptCallCounts = append(receiptCallCounts, receiptCallCount)
@@ -127,9 +128,9 @@ var ReceiptCountCommand = &cli.Command{
 		if plotEnabled {
 			plotFile := filepath.Join(plotDir, plotPrefix+".png")
 			if err := plotReceiptCallCounts(receiptCallCounts, plotFile); err != nil {
-				fmt.Printf("Warning: failed to generate receipt call count plot: %v\n", err)
+				bench.Logger().Warn("failed to generate receipt call count plot", "err", err)
 			} else {
-				fmt.Printf("Receipt call count plot saved as '%s'\n", plotFile)
+				bench.Logger().Info("receipt call count plot saved", "path", plotFile)
 			}
 		}
 
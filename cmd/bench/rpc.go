@@ -0,0 +1,133 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+
+	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+)
+
+// rpcMethodFlags select the JSON-RPC call(s) benchmarked by the rpc and load commands.
+var rpcMethodFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "method",
+		Usage: "JSON-RPC method to call; preset methods get default params unless --params is set",
+	},
+	&cli.StringFlag{
+		Name:  "params",
+		Usage: "JSON array of params for --method, e.g. '[{\"to\":\"0x...\",\"data\":\"0x...\"},\"latest\"]'",
+	},
+	&cli.Uint64Flag{
+		Name:  "log-blocks",
+		Usage: "Number of blocks, ending at the latest, queried by the eth_getLogs preset",
+		Value: 100,
+	},
+}
+
+var RPCCommand = &cli.Command{
+	Name:  "rpc",
+	Usage: "Measure latency of read RPC methods (all built-in presets unless --method is given)",
+	Flags: append(append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Path to .env file with RPC_ENDPOINT and PRIVATE_KEYS",
+			Value: ".env",
+		},
+		&cli.IntFlag{
+			Name:  "count",
+			Usage: "Number of calls per method",
+			Value: 100,
+		},
+		&cli.IntFlag{
+			Name:  "concurrency",
+			Usage: "Number of concurrent workers issuing calls",
+			Value: 1,
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "Delay between consecutive calls of a worker",
+			Value: 0,
+		},
+	}, rpcMethodFlags...), LogFlags...),
+	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
+		if err := bench.LoadEnv(c.String("env-file")); err != nil {
+			return fmt.Errorf("failed to load env: %w", err)
+		}
+
+		client, err := ethclient.Dial(bench.RPCEndpoint())
+		if err != nil {
+			return fmt.Errorf("failed to connect RPC endpoint: %w", err)
+		}
+		defer client.Close()
+
+		calls, err := resolveRPCCalls(c, client.Client(), c.String("method"))
+		if err != nil {
+			return err
+		}
+
+		count := c.Int("count")
+		concurrency := c.Int("concurrency")
+		interval := c.Duration("interval")
+
+		stats := make([]bench.RPCStats, 0, len(calls))
+		for _, call := range calls {
+			bench.Logger().Info("benchmarking RPC method", "method", call.Method, "count", count, "concurrency", concurrency)
+			stats = append(stats, bench.RunRPCBenchmark(c.Context, client.Client(), call, count, concurrency, interval))
+		}
+
+		bench.PrintRPCReport(stats)
+		return nil
+	},
+}
+
+// resolveRPCCalls builds the call for method with --params, or its preset params if it is a preset.
+// An empty method selects every preset.
+func resolveRPCCalls(c *cli.Context, client *rpc.Client, method string) ([]bench.RPCCall, error) {
+	if c.IsSet("params") && method == "" {
+		return nil, fmt.Errorf("--params requires --method")
+	}
+
+	var address common.Address
+	if signers := bench.Signers(); len(signers) > 0 {
+		address = signers[0].Address()
+	}
+
+	var calls []bench.RPCCall
+	switch {
+	case c.IsSet("params"):
+		var params []interface{}
+		if err := json.Unmarshal([]byte(c.String("params")), &params); err != nil {
+			return nil, fmt.Errorf("invalid --params, must be a JSON array: %w", err)
+		}
+		calls = append(calls, bench.RPCCall{Method: method, Params: params})
+	case slices.Contains(bench.RPCPresetNames, method):
+		call, err := bench.RPCPreset(c.Context, client, method, address, c.Uint64("log-blocks"))
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	case method != "":
+		calls = append(calls, bench.RPCCall{Method: method})
+	default:
+		for _, name := range bench.RPCPresetNames {
+			call, err := bench.RPCPreset(c.Context, client, name, address, c.Uint64("log-blocks"))
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, call)
+		}
+	}
+	return calls, nil
+}
@@ -3,8 +3,6 @@ package bench
 import (
 	"context"
 	"fmt"
-	"log"
-	"sort"
 	"time"
 
 	"github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
@@ -15,7 +13,7 @@ import (
 var BlockNumberCommand = &cli.Command{
 	Name:  "resp-time",
 	Usage: "Measure time to call eth_blockNumber RPC",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{
 			Name:  "env-file",
 			Usage: "Path to .env file with RPC_ENDPOINT",
@@ -31,8 +29,14 @@ var BlockNumberCommand = &cli.Command{
 			Usage: "Interval between calls",
 			Value: 500 * time.Millisecond,
 		},
-	},
+	}, LogFlags...),
 	Action: func(c *cli.Context) error {
+		flushLogs, err := setupLogging(c)
+		if err != nil {
+			return err
+		}
+		defer flushLogs()
+
 		if err := bench.LoadEnv(c.String("env-file")); err != nil {
 			return fmt.Errorf("failed to load env: %w", err)
 		}
@@ -46,7 +50,7 @@ var BlockNumberCommand = &cli.Command{
 		count := c.Int("count")
 		interval := c.Duration("interval")
 
-		fmt.Printf("Calling eth_blockNumber %d times with %v interval...\n", count, interval)
+		bench.Logger().Info("calling eth_blockNumber", "count", count, "interval", interval)
 
 		metrics := extractRPCTime(client, count, interval)
 		if metrics == nil {
@@ -64,47 +68,10 @@ var BlockNumberCommand = &cli.Command{
 }
 
 func extractRPCTime(client *ethclient.Client, count int, interval time.Duration) []time.Duration {
-	times := make([]time.Duration, 0, count)
-
-	ctx := context.Background()
-
-	for i := 0; i < count; i++ {
-		start := time.Now()
-		_, err := client.BlockNumber(ctx)
-		elapsed := time.Since(start)
-
-		if err != nil {
-			log.Printf("Call %d failed: %v", i+1, err)
-		} else {
-			fmt.Printf("Call %d took %v\n", i+1, elapsed)
-			times = append(times, elapsed)
-		}
-
-		if i < count-1 {
-			time.Sleep(interval)
-		}
-	}
-
-	if len(times) == 0 {
-		fmt.Println("No successful calls to measure.")
+	stats := bench.RunRPCBenchmark(context.Background(), client.Client(), bench.RPCCall{Method: "eth_blockNumber"}, count, 1, interval)
+	if len(stats.Latencies) == 0 {
+		bench.Logger().Warn("no successful calls to measure")
 		return nil
 	}
-
-	// Calculate minTime, maxTime, avg, median
-	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
-
-	minTime := times[0]
-	maxTime := times[len(times)-1]
-
-	var total time.Duration
-	for _, t := range times {
-		total += t
-	}
-	avg := total / time.Duration(len(times))
-
-	median := times[len(times)/2]
-	if len(times)%2 == 0 {
-		median = (times[len(times)/2-1] + times[len(times)/2]) / 2
-	}
-	return []time.Duration{minTime, maxTime, avg, median}
+	return []time.Duration{stats.Min(), stats.Max(), stats.Avg(), stats.Median()}
 }
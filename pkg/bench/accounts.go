@@ -0,0 +1,356 @@
+package bench
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ephemeralKeyDomain separates ephemeral key derivation from any other use of the funder key.
+const ephemeralKeyDomain = "evm-latency-bench/ephemeral"
+
+// EphemeralSigners derives count sender accounts from the first configured key, which must be held
+// locally. The same funder key always yields the same accounts.
+func EphemeralSigners(count int) ([]Signer, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no funder key configured")
+	}
+	funder, ok := signers[0].(*keySigner)
+	if !ok {
+		return nil, fmt.Errorf("ephemeral accounts require a local funder key, not a remote signer")
+	}
+
+	out := make([]Signer, 0, count)
+	for i := 0; i < count; i++ {
+		seed := crypto.Keccak256(crypto.FromECDSA(funder.key), []byte(ephemeralKeyDomain),
+			binary.BigEndian.AppendUint32(nil, uint32(i)))
+		key, err := crypto.ToECDSA(seed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive ephemeral account %d: %w", i, err)
+		}
+		out = append(out, newKeySigner(key))
+	}
+	return out, nil
+}
+
+// ParseEther parses a decimal ETH amount such as "0.05" into wei.
+func ParseEther(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 18 {
+		return nil, fmt.Errorf("invalid ETH amount %q: more than 18 decimals", s)
+	}
+	wei, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", 18-len(frac)), 10)
+	if !ok || wei.Sign() < 0 {
+		return nil, fmt.Errorf("invalid ETH amount %q", s)
+	}
+	return wei, nil
+}
+
+// FormatEther formats wei as a decimal ETH amount without trailing zeros.
+func FormatEther(wei *big.Int) string {
+	whole, frac := new(big.Int).QuoRem(wei, big.NewInt(params.Ether), new(big.Int))
+	if frac.Sign() == 0 {
+		return whole.String()
+	}
+	return whole.String() + "." + strings.TrimRight(fmt.Sprintf("%018d", new(big.Int).Abs(frac)), "0")
+}
+
+// AccountsResult summarises a fund or sweep run.
+type AccountsResult struct {
+	Accounts    int              // ephemeral accounts considered
+	Txs         int              // transfers accepted by the endpoint
+	Unconfirmed int              // transfers without a receipt when the timeout elapsed
+	Moved       *big.Int         // wei transferred by the accepted transfers
+	MaxGasCost  *big.Int         // upper bound of the fees paid for the accepted transfers, L1 data fees included
+	Failed      []AccountFailure // accounts whose transfer could not be built or was rejected
+}
+
+// AccountFailure is an ephemeral account that could not be funded or swept.
+type AccountFailure struct {
+	Address common.Address
+	Err     error
+}
+
+// FundAccounts tops up the first count ephemeral accounts to amount wei from the funder, sending the
+// transfers in JSON-RPC batches of batchSize. A rejected transfer leaves a gap in the funder's nonces,
+// so funding stops at the first batch with a rejection.
+func FundAccounts(count int, amount *big.Int, batchSize int, pollInterval, timeout time.Duration) (AccountsResult, error) {
+	result := AccountsResult{Accounts: count, Moved: new(big.Int), MaxGasCost: new(big.Int)}
+	if batchSize < 1 {
+		return result, fmt.Errorf("batch size must be at least 1")
+	}
+	accounts, err := EphemeralSigners(count)
+	if err != nil {
+		return result, err
+	}
+	client, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return result, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	chainID, err := getChainID(ctx, client)
+	if err != nil {
+		return result, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	funder := signers[0]
+	gasTipCap, gasFeeCap, err := suggestFees(ctx, client)
+	if err != nil {
+		return result, err
+	}
+
+	var recipients []common.Address
+	var values []*big.Int
+	for _, account := range accounts {
+		balance, err := client.BalanceAt(ctx, account.Address(), nil)
+		if err != nil {
+			return result, fmt.Errorf("failed to get balance of %s: %w", account.Address().Hex(), err)
+		}
+		if balance.Cmp(amount) >= 0 {
+			continue
+		}
+		recipients = append(recipients, account.Address())
+		values = append(values, new(big.Int).Sub(amount, balance))
+	}
+	if len(recipients) == 0 {
+		Logger().Info("all ephemeral accounts already funded", "accounts", count)
+		return result, nil
+	}
+
+	nonce, err := client.PendingNonceAt(ctx, funder.Address())
+	if err != nil {
+		return result, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	sign := func(i int, nonce uint64) (*types.Transaction, error) {
+		tx, err := funder.SignTx(ctx, types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       selfTransferGas,
+			To:        &recipients[i],
+			Value:     values[i],
+		}), chainID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		}
+		return tx, nil
+	}
+
+	// Every funding transfer has the same size, so the L1 data fee of the first stands for all of them.
+	sample, err := sign(0, nonce)
+	if err != nil {
+		return result, err
+	}
+	l1Fee, err := reserveL1Fee(ctx, client, sample)
+	if err != nil {
+		return result, err
+	}
+	perTx := new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(selfTransferGas))
+	perTx.Add(perTx, l1Fee)
+
+	total := new(big.Int)
+	for _, v := range values {
+		total.Add(total, v)
+	}
+	funderBalance, err := client.BalanceAt(ctx, funder.Address(), nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to get funder balance: %w", err)
+	}
+	need := new(big.Int).Mul(perTx, big.NewInt(int64(len(values))))
+	if need.Add(need, total); funderBalance.Cmp(need) < 0 {
+		return result, fmt.Errorf("funder %s has %s ETH, needs up to %s ETH", funder.Address().Hex(),
+			FormatEther(funderBalance), FormatEther(need))
+	}
+	Logger().Info("funding ephemeral accounts", "accounts", len(recipients), "total_eth", FormatEther(total))
+
+	for start := 0; start < len(recipients); start += batchSize {
+		end := min(start+batchSize, len(recipients))
+		txs := make([]*types.Transaction, 0, end-start)
+		for i := start; i < end; i++ {
+			signedTx, err := sign(i, nonce)
+			if err != nil {
+				return result, err
+			}
+			txs = append(txs, signedTx)
+			nonce++
+		}
+
+		group, err := runBatchGroup(txs, true, pollInterval, timeout)
+		if err != nil {
+			return result, err
+		}
+		result.Unconfirmed += group.Unconfirmed
+		for i, tx := range txs {
+			if err := group.Rejected[i]; err != nil {
+				result.Failed = append(result.Failed, AccountFailure{Address: *tx.To(), Err: err})
+				continue
+			}
+			result.Txs++
+			result.Moved.Add(result.Moved, tx.Value())
+			result.MaxGasCost.Add(result.MaxGasCost, perTx)
+		}
+		Logger().Info("funding batch confirmed", "txs", len(txs), "unconfirmed", group.Unconfirmed, "confirm_ms", group.ConfirmTime)
+		if len(group.Rejected) > 0 {
+			return result, fmt.Errorf("funding stopped: %d transfers of the batch were rejected", len(group.Rejected))
+		}
+	}
+	return result, nil
+}
+
+// SweepAccounts returns the balance of the first count ephemeral accounts to the funder, keeping back the
+// maximum gas cost and L1 data fee of the transfer. Transfers are sent in JSON-RPC batches of batchSize.
+// An account that cannot be swept is recorded in Failed and the sweep continues with the next one.
+func SweepAccounts(count, batchSize int, pollInterval, timeout time.Duration) (AccountsResult, error) {
+	result := AccountsResult{Accounts: count, Moved: new(big.Int), MaxGasCost: new(big.Int)}
+	if batchSize < 1 {
+		return result, fmt.Errorf("batch size must be at least 1")
+	}
+	accounts, err := EphemeralSigners(count)
+	if err != nil {
+		return result, err
+	}
+	client, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return result, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	chainID, err := getChainID(ctx, client)
+	if err != nil {
+		return result, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	funderAddress := signers[0].Address()
+	gasTipCap, gasFeeCap, err := suggestFees(ctx, client)
+	if err != nil {
+		return result, err
+	}
+	maxGasCost := new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(selfTransferGas))
+
+	var txs []*types.Transaction
+	var from []common.Address
+	var reserved []*big.Int
+	flush := func() error {
+		if len(txs) == 0 {
+			return nil
+		}
+		group, err := runBatchGroup(txs, true, pollInterval, timeout)
+		if err != nil {
+			return err
+		}
+		result.Unconfirmed += group.Unconfirmed
+		for i, tx := range txs {
+			if err := group.Rejected[i]; err != nil {
+				result.Failed = append(result.Failed, AccountFailure{Address: from[i], Err: err})
+				continue
+			}
+			result.Txs++
+			result.Moved.Add(result.Moved, tx.Value())
+			result.MaxGasCost.Add(result.MaxGasCost, reserved[i])
+		}
+		Logger().Info("sweep batch confirmed", "txs", len(txs), "rejected", len(group.Rejected),
+			"unconfirmed", group.Unconfirmed, "confirm_ms", group.ConfirmTime)
+		txs, from, reserved = txs[:0], from[:0], reserved[:0]
+		return nil
+	}
+	fail := func(account Signer, err error) {
+		Logger().Warn("failed to sweep account", "account", account.Address().Hex(), "err", err)
+		result.Failed = append(result.Failed, AccountFailure{Address: account.Address(), Err: err})
+	}
+
+	for _, account := range accounts {
+		balance, err := client.BalanceAt(ctx, account.Address(), nil)
+		if err != nil {
+			fail(account, fmt.Errorf("failed to get balance: %w", err))
+			continue
+		}
+		if balance.Cmp(maxGasCost) <= 0 {
+			continue
+		}
+		nonce, err := client.PendingNonceAt(ctx, account.Address())
+		if err != nil {
+			fail(account, fmt.Errorf("failed to get nonce: %w", err))
+			continue
+		}
+		sign := func(value *big.Int) (*types.Transaction, error) {
+			tx, err := account.SignTx(ctx, types.NewTx(&types.DynamicFeeTx{
+				ChainID:   chainID,
+				Nonce:     nonce,
+				GasTipCap: gasTipCap,
+				GasFeeCap: gasFeeCap,
+				Gas:       selfTransferGas,
+				To:        &funderAddress,
+				Value:     value,
+			}), chainID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign transaction: %w", err)
+			}
+			return tx, nil
+		}
+
+		// The L1 data fee depends on the encoded transfer, so it is quoted on a draft that keeps back the
+		// gas cost only. The final transfer moves less value and is never larger.
+		draft, err := sign(new(big.Int).Sub(balance, maxGasCost))
+		if err != nil {
+			fail(account, err)
+			continue
+		}
+		l1Fee, err := reserveL1Fee(ctx, client, draft)
+		if err != nil {
+			fail(account, err)
+			continue
+		}
+		reserve := new(big.Int).Add(maxGasCost, l1Fee)
+		if balance.Cmp(reserve) <= 0 {
+			continue
+		}
+		signedTx, err := sign(new(big.Int).Sub(balance, reserve))
+		if err != nil {
+			fail(account, err)
+			continue
+		}
+		txs = append(txs, signedTx)
+		from = append(from, account.Address())
+		reserved = append(reserved, reserve)
+
+		if len(txs) == batchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("%d of %d ephemeral accounts could not be swept", len(result.Failed), count)
+	}
+	return result, nil
+}
+
+// PrintAccountsReport prints the outcome of a fund or sweep run.
+func PrintAccountsReport(action string, result AccountsResult) {
+	fmt.Printf("\n%s: %d of %d ephemeral accounts, %s ETH moved, at most %s ETH in fees\n",
+		action, result.Txs, result.Accounts, FormatEther(result.Moved), FormatEther(result.MaxGasCost))
+	if result.Unconfirmed > 0 {
+		fmt.Printf("WARNING: %d transfers were not confirmed before the timeout\n", result.Unconfirmed)
+	}
+	if len(result.Failed) > 0 {
+		fmt.Printf("FAILED: %d accounts\n", len(result.Failed))
+		for _, f := range result.Failed {
+			fmt.Printf("  %s: %v\n", f.Address.Hex(), f.Err)
+		}
+	}
+}
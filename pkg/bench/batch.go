@@ -0,0 +1,232 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// BatchGroupResult is the outcome of sending and confirming one group of transactions.
+type BatchGroupResult struct {
+	Batched     bool
+	GroupIndex  int
+	Size        int
+	SendTime    int64   // milliseconds to submit every tx of the group
+	ConfirmTime int64   // milliseconds from the group send start until its last receipt
+	Requests    int     // HTTP requests made for sending and polling
+	TxTimes     []int64 // per confirmed tx, milliseconds from the group send start until its receipt was seen
+	Unconfirmed int     // txs without a receipt when the timeout elapsed
+	// Rejected holds the send error of each tx the endpoint refused, by index in the group. Rejected txs
+	// are not polled.
+	Rejected map[int]error
+}
+
+// RunBatchBenchmark sends txCount transactions per mode in groups of batchSize, alternating between
+// groups sent and polled with one JSON-RPC batch request per round and groups using one request per
+// transaction, so that both modes see the same chain conditions.
+func RunBatchBenchmark(txCount, batchSize int, pollInterval, timeout time.Duration) ([]BatchGroupResult, error) {
+	if batchSize < 1 {
+		return nil, fmt.Errorf("batch size must be at least 1")
+	}
+	client, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	chainID, err := getChainID(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	signer := signers[0]
+	fromAddress := signer.Address()
+
+	nonce, err := client.PendingNonceAt(ctx, fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	var results []BatchGroupResult
+	for sent, group := 0, 0; sent < txCount; sent, group = sent+batchSize, group+1 {
+		size := min(batchSize, txCount-sent)
+		for _, batched := range []bool{false, true} {
+			txs := make([]*types.Transaction, size)
+			for i := range txs {
+				txs[i], err = signSelfTransfer(ctx, client, chainID, signer, nonce)
+				if err != nil {
+					return nil, err
+				}
+				nonce++
+			}
+
+			result, err := runBatchGroup(txs, batched, pollInterval, timeout)
+			if err != nil {
+				return nil, err
+			}
+			for i, tx := range txs {
+				if err := result.Rejected[i]; err != nil {
+					return nil, fmt.Errorf("failed to send transaction %s: %w", tx.Hash().Hex(), err)
+				}
+			}
+			result.GroupIndex = group + 1
+			Logger().Info("batch group confirmed", "group", result.GroupIndex, "batched", batched, "size", size,
+				"send_ms", result.SendTime, "confirm_ms", result.ConfirmTime, "requests", result.Requests)
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// runBatchGroup submits txs and polls for their receipts, either batching all calls of a round into one
+// request or issuing them one by one. Txs refused by the endpoint are recorded in Rejected; only a
+// failure of the whole request is returned as an error.
+func runBatchGroup(txs []*types.Transaction, batched bool, pollInterval, timeout time.Duration) (BatchGroupResult, error) {
+	result := BatchGroupResult{Batched: batched, Size: len(txs), Rejected: make(map[int]error)}
+
+	rawTxs := make([]string, len(txs))
+	for i, tx := range txs {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return result, fmt.Errorf("failed to marshal signed tx: %w", err)
+		}
+		rawTxs[i] = hexutil.Encode(raw)
+	}
+
+	start := time.Now()
+	if batched {
+		reqs := make([]rpcRequest, len(rawTxs))
+		for i, raw := range rawTxs {
+			reqs[i] = rpcRequest{JSONRPC: "2.0", Method: "eth_sendRawTransaction", Params: []interface{}{raw}, ID: i + 1}
+		}
+		result.Requests++
+		resps, err := sendRPCBatch(rpcEndpoint, reqs)
+		if err != nil {
+			return result, fmt.Errorf("failed to send transaction batch: %w", err)
+		}
+		for i, resp := range resps {
+			if resp.Error != nil {
+				result.Rejected[i] = fmt.Errorf("RPC error %d: %s", resp.Error.Code, resp.Error.Message)
+			}
+		}
+	} else {
+		for i, raw := range rawTxs {
+			result.Requests++
+			if _, err := callRPC(rpcEndpoint, "eth_sendRawTransaction", raw); err != nil {
+				result.Rejected[i] = err
+			}
+		}
+	}
+	result.SendTime = time.Since(start).Milliseconds()
+
+	pending := make([]int, 0, len(txs))
+	for i := range txs {
+		if result.Rejected[i] == nil {
+			pending = append(pending, i)
+		}
+	}
+	for len(pending) > 0 && time.Since(start) < timeout {
+		var found []bool
+		if batched {
+			reqs := make([]rpcRequest, len(pending))
+			for j, i := range pending {
+				reqs[j] = rpcRequest{JSONRPC: "2.0", Method: "eth_getTransactionReceipt", Params: []interface{}{txs[i].Hash()}, ID: j + 1}
+			}
+			result.Requests++
+			resps, err := sendRPCBatch(rpcEndpoint, reqs)
+			if err != nil {
+				Logger().Warn("receipt batch poll failed", "err", err)
+			}
+			found = make([]bool, len(pending))
+			for j, resp := range resps {
+				found[j] = resp.Error == nil && hasResult(resp.Result)
+			}
+		} else {
+			found = make([]bool, len(pending))
+			for j, i := range pending {
+				result.Requests++
+				res, err := callRPC(rpcEndpoint, "eth_getTransactionReceipt", txs[i].Hash())
+				found[j] = err == nil && hasResult(res)
+			}
+		}
+
+		elapsed := time.Since(start).Milliseconds()
+		still := pending[:0]
+		for j, i := range pending {
+			if found[j] {
+				result.TxTimes = append(result.TxTimes, elapsed)
+				Logger().Debug("receipt found", "hash", txs[i].Hash().Hex(), "batched", batched, "elapsed_ms", elapsed)
+			} else {
+				still = append(still, i)
+			}
+		}
+		pending = still
+
+		if len(pending) > 0 {
+			time.Sleep(pollInterval)
+		}
+	}
+	result.ConfirmTime = time.Since(start).Milliseconds()
+	result.Unconfirmed = len(pending)
+
+	return result, nil
+}
+
+// hasResult reports whether a raw JSON-RPC result holds a value rather than null.
+func hasResult(raw []byte) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && !bytes.Equal(raw, []byte("null"))
+}
+
+// PrintBatchReport compares batched and unbatched groups sent for the same workload.
+func PrintBatchReport(results []BatchGroupResult) {
+	if len(results) == 0 {
+		Logger().Warn("no results to report")
+		return
+	}
+
+	fmt.Println("\nBATCHED VS UNBATCHED:")
+	fmt.Printf("%-10s %-7s %-7s %-10s %-10s %-14s %-12s %-12s %-12s\n",
+		"MODE", "GROUPS", "TXS", "REQUESTS", "REQ/TX", "MED SEND (ms)", "MED TX (ms)", "P90 TX (ms)", "UNCONFIRMED")
+	fmt.Println("-------------------------------------------------------------------------------------------------------")
+
+	for _, batched := range []bool{false, true} {
+		var groups, txs, requests, unconfirmed int
+		var sendTimes, txTimes []int64
+		for _, r := range results {
+			if r.Batched != batched {
+				continue
+			}
+			groups++
+			txs += r.Size
+			requests += r.Requests
+			unconfirmed += r.Unconfirmed
+			sendTimes = append(sendTimes, r.SendTime)
+			txTimes = append(txTimes, r.TxTimes...)
+		}
+		if groups == 0 {
+			continue
+		}
+
+		mode := "unbatched"
+		if batched {
+			mode = "batched"
+		}
+		medTx, p90Tx := "-", "-"
+		if len(txTimes) > 0 {
+			sorted := append([]int64(nil), txTimes...)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+			medTx, p90Tx = fmt.Sprintf("%d", median(sorted)), fmt.Sprintf("%d", percentile(sorted, 90))
+		}
+		fmt.Printf("%-10s %-7d %-7d %-10d %-10.2f %-14d %-12s %-12s %-12d\n",
+			mode, groups, txs, requests, float64(requests)/float64(txs), median(sendTimes), medTx, p90Tx, unconfirmed)
+	}
+}
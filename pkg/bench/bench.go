@@ -3,23 +3,52 @@ package bench
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 var (
-	rpcEndpoint string
-	privKeys    []string
+	rpcEndpoint       string
+	extraRPCEndpoints []string
+	privKeys          []string
+	signers           []Signer
 )
 
 type Result struct {
 	TxIndex     int
-	TxHash      string
-	SendTime    int64 // milliseconds
-	ConfirmTime int64 // milliseconds
-	TotalTime   int64 // milliseconds
+	TxHash      string // hash of the included tx, which is a replacement if the stuck policy replaced the original
+	SendTime    int64  // milliseconds
+	ConfirmTime int64  // milliseconds
+	TotalTime   int64  // milliseconds
+	SignTime    int64  // microseconds, since signing takes well under a millisecond; not part of TotalTime
+	// Warmup marks transactions sent before the measured ones, which are left out of the statistics.
+	Warmup bool
+
+	SentAt         int64 // local unix milliseconds when the send started
+	BlockNumber    uint64
+	BlockTimestamp int64 // inclusion block timestamp, unix milliseconds; 0 if unknown
+	// ChainLatency is BlockTimestamp minus SentAt, mixing the chain and local clocks.
+	ChainLatency int64
+	// ChainLatencyCorrected is ChainLatency adjusted by the estimated local clock offset.
+	ChainLatencyCorrected int64
+
+	// Levels holds the milliseconds from the send start to each confirmation level that was observed.
+	Levels map[ConfirmationLevel]int64
+
+	// Cost is the fee paid according to the receipt; nil if it could not be read.
+	Cost *TxCost
+
+	// Outcome is which transaction at the nonce was included: OutcomeIncluded, OutcomeReplaced or
+	// OutcomeCancelled. Replacements lists the actions the stuck policy took before that.
+	Outcome      string
+	Replacements []Replacement
+
+	// Reorg holds the outcome of re-verifying the inclusion block; nil unless reorg monitoring was enabled.
+	Reorg *ReorgInfo
 }
 
 func LoadEnv(path string) error {
@@ -30,25 +59,74 @@ func LoadEnv(path string) error {
 	if rpcEndpoint == "" {
 		return errors.New("RPC_ENDPOINT not set in env file")
 	}
-	keys := os.Getenv("PRIVATE_KEYS")
-	if keys == "" {
-		return errors.New("PRIVATE_KEYS not set in env file")
+	privKeys = SplitList(os.Getenv("PRIVATE_KEYS"))
+	var err error
+	signers, err = loadSigners()
+	if err != nil {
+		return err
+	}
+	if len(signers) == 0 {
+		return errors.New("no keys configured: set PRIVATE_KEYS, KEYSTORE_FILES, MNEMONIC or REMOTE_SIGNER_URL in env file")
 	}
-	privKeys = strings.Split(keys, ",")
-	for i := range privKeys {
-		privKeys[i] = strings.TrimSpace(privKeys[i])
+	if s := os.Getenv("EPHEMERAL_ACCOUNTS"); s != "" {
+		count, err := strconv.Atoi(s)
+		if err != nil || count < 0 {
+			return fmt.Errorf("invalid EPHEMERAL_ACCOUNTS: %q", s)
+		}
+		ephemeral, err := EphemeralSigners(count)
+		if err != nil {
+			return err
+		}
+		signers = append(signers, ephemeral...)
+	}
+	extraRPCEndpoints = SplitList(os.Getenv("EXTRA_RPC_ENDPOINTS"))
+
+	expectedChainID = nil
+	if s := os.Getenv("EXPECTED_CHAIN_ID"); s != "" {
+		id, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("invalid EXPECTED_CHAIN_ID: %q", s)
+		}
+		expectedChainID = id
 	}
-	if len(privKeys) == 0 {
-		return errors.New("no private keys found in PRIVATE_KEYS")
+	allowedMainnets = nil
+	for _, s := range SplitList(os.Getenv("ALLOWED_MAINNETS")) {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid chain ID in ALLOWED_MAINNETS: %q", s)
+		}
+		allowedMainnets = append(allowedMainnets, id)
 	}
 	return nil
 }
 
+// SplitList splits a comma-separated list, trimming spaces and dropping empty entries.
+func SplitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 // RPCEndpoint returns the loaded RPC endpoint string
 func RPCEndpoint() string {
 	return rpcEndpoint
 }
 
+// ExtraRPCEndpoints returns the optional additional RPC endpoints loaded from EXTRA_RPC_ENDPOINTS
+func ExtraRPCEndpoints() []string {
+	return extraRPCEndpoints
+}
+
+// Signers returns the signers of every configured key source, plaintext keys first and the
+// EPHEMERAL_ACCOUNTS derived from the first key last
+func Signers() []Signer {
+	return signers
+}
+
 // PrivKeys returns the loaded private keys slice
 func PrivKeys() []string {
 	return privKeys
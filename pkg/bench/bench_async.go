@@ -2,14 +2,17 @@ package bench
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func RunBenchmarkAsync(txCount int, pollInterval time.Duration) ([]Result, error) {
@@ -19,82 +22,207 @@ func RunBenchmarkAsync(txCount int, pollInterval time.Duration) ([]Result, error
 	}
 	ctx := context.Background()
 
-	results := make([]Result, 0, txCount)
+	results := make([]Result, 0, warmupTxs+txCount)
 
-	privKey, err := crypto.HexToECDSA(privKeys[0])
+	signer := signers[0]
+	fromAddress := signer.Address()
+
+	chainID, err := getChainID(ctx, client)
 	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %w", err)
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
 	}
-	fromAddress := crypto.PubkeyToAddress(privKey.PublicKey)
-
-	nonce, err := client.PendingNonceAt(ctx, fromAddress)
+	nonce, err := preflight(ctx, client, chainID, signer, warmupTxs+txCount, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return nil, err
+	}
+
+	guard := spend
+	var planned []*types.Transaction
+
+	var finality *finalityTracker
+	if finalityTimeout > 0 {
+		finality = startFinalityTracker(ctx, client.Client(), finalityPollInterval)
+		defer finality.stop()
+	}
+	var reorgs *reorgMonitor
+	if reorgDepth > 0 {
+		reorgs = startReorgMonitor(ctx, client, reorgDepth, reorgPollInterval)
+		defer reorgs.stop()
 	}
-	for i := 0; i < txCount; i++ {
+
+	settle()
+	for i := 0; i < warmupTxs+txCount; i++ {
 		time.Sleep(10 * time.Millisecond)
 
-		log.Printf("[INFO] Tx %d: nonce %d from %s", i+1, nonce, fromAddress.Hex())
+		Logger().Info("sending tx", "tx", i+1, "nonce", nonce, "from", fromAddress.Hex())
+		txCtx, txSpan := startTxSpan(ctx, "async", i+1, nonce)
 
 		toAddress := fromAddress  // self-transfer
 		value := big.NewInt(1e10) // 0.001 ETH
 		gasLimit := uint64(21000)
-		gasPrice, err := client.SuggestGasPrice(ctx)
+		feeCtx, feeSpan := tracer.Start(txCtx, "fee_lookup")
+		gasPrice, err := client.SuggestGasPrice(feeCtx)
+		endSpan(feeSpan, err)
 		if err != nil {
+			endSpan(txSpan, err)
 			return nil, fmt.Errorf("failed to get gas price: %w", err)
 		}
 		gasPrice = gasPrice.Mul(gasPrice, big.NewInt(2))
 
 		tx := types.NewTransaction(nonce, toAddress, value, gasLimit, gasPrice, nil)
-		chainID, err := client.NetworkID(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get network ID: %w", err)
-		}
-
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privKey)
+		_, signSpan := tracer.Start(txCtx, "sign")
+		signStart := time.Now()
+		signedTx, err := signer.SignTx(txCtx, tx, chainID)
+		signDuration := time.Since(signStart)
+		endSpan(signSpan, err)
 		if err != nil {
+			endSpan(txSpan, err)
 			return nil, fmt.Errorf("failed to sign transaction: %w", err)
 		}
+		if err := guard.allow(signedTx); err != nil {
+			txSpan.End()
+			Logger().Warn("stopping run at spend limit", "tx", i+1, "reason", err)
+			break
+		}
+		if dryRun {
+			txSpan.End()
+			planned = append(planned, signedTx)
+			guard.record(signedTx, nil)
+			nonce++
+			continue
+		}
+		txHash := signedTx.Hash()
+		txSpan.SetAttributes(attribute.String("tx.hash", txHash.Hex()))
 
+		sendCtx, sendSpan := tracer.Start(txCtx, "send_transaction")
 		sendStart := time.Now()
-		err = client.SendTransaction(ctx, signedTx)
+		err = client.SendTransaction(sendCtx, signedTx)
 		sendEnd := time.Now()
+		endSpan(sendSpan, err)
 		if err != nil {
+			endSpan(txSpan, err)
+			observer.TxFailed(i+1, err)
 			return nil, fmt.Errorf("failed to send transaction: %w", err)
 		}
 		sendDuration := sendEnd.Sub(sendStart)
-
-		txHash := signedTx.Hash()
-		log.Printf("[INFO] Tx %d: sent %s in %v", i+1, txHash.Hex(), sendDuration)
-
-		confirmStart := time.Now()
-		var receipt *types.Receipt
-		pollCount := 0
-		for {
-			receipt, err = client.TransactionReceipt(ctx, txHash)
-			if err == nil && receipt != nil {
-				break
-			}
-			pollCount++
-			log.Printf("[DEBUG] Tx %d: polling receipt attempt %d", i+1, pollCount)
-			time.Sleep(pollInterval)
+		observer.TxSent(i+1, txHash.Hex())
+		levels := newLevelRecorder(i+1, sendStart)
+		levels.observe(LevelMempool, sendEnd)
+
+		Logger().Info("tx sent", "tx", i+1, "hash", txHash.Hex(), "send_time", sendDuration, "sign_time", signDuration)
+
+		confirmCtx, confirmSpan := tracer.Start(txCtx, "confirm")
+		stopTracking := func() {}
+		if trackLevels {
+			trackCtx, cancel := context.WithCancel(confirmCtx)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				trackPreconfirmation(trackCtx, client.Client(), txHash, pollInterval, levels)
+			}()
+			stopTracking = func() { cancel(); <-done }
 		}
+		confirmStart := time.Now()
+		included, receipt, pollCount, replacements, outcome, err := confirmWithPolicy(confirmCtx, client, i+1, signedTx, signer,
+			chainID, pollInterval, sendStart, guard)
 		confirmEnd := time.Now()
+		stopTracking()
+		if err != nil {
+			endSpan(confirmSpan, err)
+			endSpan(txSpan, err)
+			observer.TxFailed(i+1, err)
+			return nil, fmt.Errorf("failed to get receipt: %w", err)
+		}
 		confirmDuration := confirmEnd.Sub(confirmStart)
+		levels.observe(LevelIncluded, confirmEnd)
+		confirmSpan.SetAttributes(
+			attribute.Int("poll.count", pollCount),
+			attribute.Int64("block.number", receipt.BlockNumber.Int64()),
+		)
+		confirmSpan.End()
+		txSpan.End()
 
-		log.Printf("[INFO] Tx %d: receipt confirmed in %v (polls: %d)\n\n", i+1, confirmDuration, pollCount)
+		Logger().Info("receipt confirmed", "tx", i+1, "confirm_time", confirmDuration, "polls", pollCount)
 
 		totalDuration := sendDuration + confirmDuration
 
-		results = append(results, Result{
+		result := Result{
 			TxIndex:     i + 1,
-			TxHash:      txHash.Hex(),
+			TxHash:      receipt.TxHash.Hex(),
+			Warmup:      i < warmupTxs,
 			SendTime:    sendDuration.Milliseconds(),
 			ConfirmTime: confirmDuration.Milliseconds(),
 			TotalTime:   totalDuration.Milliseconds(),
-		})
+			SignTime:    signDuration.Microseconds(),
+			Levels:      levels.snapshot(),
+
+			Outcome:      outcome,
+			Replacements: replacements,
+		}
+		recordInclusion(ctx, client.Client(), &result, receipt.BlockNumber, sendStart)
+		if result.Cost, err = fetchTxCost(ctx, client.Client(), receipt.TxHash); err != nil {
+			Logger().Warn("failed to get tx cost", "tx", i+1, "err", err)
+		}
+		guard.record(included, result.Cost)
+		if finality != nil {
+			finality.add(len(results), i+1, receipt.BlockNumber.Uint64(), sendStart)
+		}
+		if reorgs != nil {
+			reorgs.add(len(results), i+1, receipt.TxHash, receipt.BlockNumber.Uint64(), receipt.BlockHash)
+		}
+		results = append(results, result)
+		observer.TxConfirmed(result)
 		nonce++
 	}
 
+	if dryRun {
+		reportDryRunPlan("async", planned)
+		return results, nil
+	}
+
+	if finality != nil {
+		for idx, levels := range finality.wait(finalityTimeout) {
+			for level, ms := range levels {
+				results[idx].Levels[level] = ms
+			}
+		}
+	}
+	if reorgs != nil {
+		for idx, info := range reorgs.wait(reorgTimeout) {
+			results[idx].Reorg = &info
+		}
+	}
+
 	return results, nil
 }
+
+// pollReceipt queries client for the receipt of txHash every pollInterval until it is available or ctx is done.
+// It returns the receipt and the number of polls that did not find it.
+func pollReceipt(ctx context.Context, client *ethclient.Client, txIndex int, txHash common.Hash, pollInterval time.Duration) (*types.Receipt, int, error) {
+	return pollReceiptWith(ctx, client, txIndex, txHash, FixedPoll{Interval: pollInterval})
+}
+
+// pollReceiptWith is pollReceipt with the delay between polls chosen by strategy.
+func pollReceiptWith(ctx context.Context, client *ethclient.Client, txIndex int, txHash common.Hash, strategy PollStrategy) (*types.Receipt, int, error) {
+	pollCount := 0
+	for {
+		pollCtx, pollSpan := tracer.Start(ctx, "receipt_poll",
+			trace.WithAttributes(attribute.Int("poll.attempt", pollCount+1)))
+		receipt, err := client.TransactionReceipt(pollCtx, txHash)
+		pollSpan.End()
+		if err == nil && receipt != nil {
+			return receipt, pollCount, nil
+		}
+		if err != nil && !errors.Is(err, ethereum.NotFound) && ctx.Err() == nil {
+			observer.PollFailed(txIndex, err)
+		}
+		pollCount++
+		Logger().Debug("polling receipt", "tx", txIndex, "attempt", pollCount)
+
+		select {
+		case <-ctx.Done():
+			return nil, pollCount, ctx.Err()
+		case <-time.After(strategy.Delay(pollCount)):
+		}
+	}
+}
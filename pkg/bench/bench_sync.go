@@ -6,14 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/big"
 	"net/http"
 	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type rpcRequest struct {
@@ -40,13 +40,74 @@ func getChainID(ctx context.Context, client *ethclient.Client) (*big.Int, error)
 }
 
 func sendRawTransactionSyncWithMethod(rpcURL, rawTxHex, method string) (json.RawMessage, error) {
+	return callRPC(rpcURL, method, rawTxHex)
+}
+
+// callRPC performs a single JSON-RPC call over HTTP and returns its raw result.
+func callRPC(rpcURL, method string, params ...interface{}) (json.RawMessage, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
 	req := rpcRequest{
 		JSONRPC: "2.0",
 		Method:  method,
-		Params:  []interface{}{rawTxHex},
+		Params:  params,
 		ID:      1,
 	}
-	reqBytes, err := json.Marshal(req)
+	body, err := postRPC(rpcURL, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		return nil, fmt.Errorf("unmarshal RPC response failed: %w", err)
+	}
+
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("RPC error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+
+	return rpcResp.Result, nil
+}
+
+// sendRPCBatch sends reqs as a single JSON-RPC batch and returns the responses in the order of reqs.
+// Errors of individual calls are left in the responses for the caller to inspect.
+func sendRPCBatch(rpcURL string, reqs []rpcRequest) ([]rpcResponse, error) {
+	body, err := postRPC(rpcURL, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResps []rpcResponse
+	if err := json.Unmarshal(body, &rpcResps); err != nil {
+		// Providers that reject the whole batch answer with a single error object.
+		var single rpcResponse
+		if json.Unmarshal(body, &single) == nil && single.Error != nil {
+			return nil, fmt.Errorf("RPC error %d: %s", single.Error.Code, single.Error.Message)
+		}
+		return nil, fmt.Errorf("unmarshal RPC batch response failed: %w", err)
+	}
+
+	// Batch responses may come back in any order.
+	byID := make(map[int]rpcResponse, len(rpcResps))
+	for _, r := range rpcResps {
+		byID[r.ID] = r
+	}
+	ordered := make([]rpcResponse, len(reqs))
+	for i, req := range reqs {
+		r, ok := byID[req.ID]
+		if !ok {
+			return nil, fmt.Errorf("RPC batch response missing id %d", req.ID)
+		}
+		ordered[i] = r
+	}
+	return ordered, nil
+}
+
+// postRPC posts payload as JSON to rpcURL and returns the raw response body.
+func postRPC(rpcURL string, payload interface{}) ([]byte, error) {
+	reqBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("marshal RPC request failed: %w", err)
 	}
@@ -61,21 +122,10 @@ func sendRawTransactionSyncWithMethod(rpcURL, rawTxHex, method string) (json.Raw
 	if err != nil {
 		return nil, fmt.Errorf("read RPC response failed: %w", err)
 	}
-
-	var rpcResp rpcResponse
-	if err := json.Unmarshal(body, &rpcResp); err != nil {
-		return nil, fmt.Errorf("unmarshal RPC response failed: %w", err)
-	}
-
-	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("RPC error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
-	}
-
-	return rpcResp.Result, nil
+	return body, nil
 }
 
 func RunBenchmarkSync(txCount int) ([]Result, error) {
-	time.Sleep(10 * time.Second)
 	client, err := ethclient.Dial(rpcEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
@@ -91,38 +141,49 @@ func RunBenchmarkSync(txCount int) ([]Result, error) {
 	specialChainID := big.NewInt(6342)
 	useRealtimeMethod := chainID.Cmp(specialChainID) == 0
 
+	// The realtime API returns the receipt from a preconfirmed mini block, before the canonical block.
 	var rpcMethod string
+	var syncLevel ConfirmationLevel
 	if useRealtimeMethod {
 		rpcMethod = "realtime_sendRawTransaction"
+		syncLevel = LevelPreconfirmed
 	} else {
 		rpcMethod = "eth_sendRawTransactionSync"
+		syncLevel = LevelIncluded
 	}
 
-	results := make([]Result, 0, txCount)
+	results := make([]Result, 0, warmupTxs+txCount)
 
-	privKey, err := crypto.HexToECDSA(privKeys[0])
-	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %w", err)
-	}
-	fromAddress := crypto.PubkeyToAddress(privKey.PublicKey)
+	signer := signers[0]
+	fromAddress := signer.Address()
 
-	nonce, err := client.PendingNonceAt(ctx, fromAddress)
+	nonce, err := preflight(ctx, client, chainID, signer, warmupTxs+txCount, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return nil, err
 	}
 
-	for i := 0; i < txCount; i++ {
+	guard := spend
+	var planned []*types.Transaction
+	settle()
+	for i := 0; i < warmupTxs+txCount; i++ {
+		txCtx, txSpan := startTxSpan(ctx, "sync", i+1, nonce)
+
 		toAddress := fromAddress  // self-transfer
 		value := big.NewInt(1e10) // 0.00000000001 ETH
 		gasLimit := uint64(21000)
 
-		gasTipCap, err := client.SuggestGasTipCap(ctx)
+		feeCtx, feeSpan := tracer.Start(txCtx, "fee_lookup")
+		gasTipCap, err := client.SuggestGasTipCap(feeCtx)
 		if err != nil {
+			endSpan(feeSpan, err)
+			endSpan(txSpan, err)
 			return nil, fmt.Errorf("failed to get gas tip cap: %w", err)
 		}
 
-		gasFeeCap, err := client.SuggestGasPrice(ctx)
+		gasFeeCap, err := client.SuggestGasPrice(feeCtx)
+		endSpan(feeSpan, err)
 		if err != nil {
+			endSpan(txSpan, err)
 			return nil, fmt.Errorf("failed to get gas fee cap: %w", err)
 		}
 		gasFeeCap = gasFeeCap.Mul(gasFeeCap, big.NewInt(2))
@@ -141,49 +202,98 @@ func RunBenchmarkSync(txCount int) ([]Result, error) {
 		tx := types.NewTx(txData)
 
 		// Use LondonSigner for EIP-1559 transactions
-		signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), privKey)
+		_, signSpan := tracer.Start(txCtx, "sign")
+		signStart := time.Now()
+		signedTx, err := signer.SignTx(txCtx, tx, chainID)
+		signDuration := time.Since(signStart)
+		endSpan(signSpan, err)
 		if err != nil {
+			endSpan(txSpan, err)
 			return nil, fmt.Errorf("failed to sign transaction: %w", err)
 		}
+		if err := guard.allow(signedTx); err != nil {
+			txSpan.End()
+			Logger().Warn("stopping run at spend limit", "tx", i+1, "reason", err)
+			break
+		}
+		if dryRun {
+			txSpan.End()
+			planned = append(planned, signedTx)
+			guard.record(signedTx, nil)
+			nonce++
+			continue
+		}
+		txHash := signedTx.Hash()
+		txSpan.SetAttributes(attribute.String("tx.hash", txHash.Hex()))
 
 		rawTxBytes, err := signedTx.MarshalBinary()
 		if err != nil {
+			endSpan(txSpan, err)
 			return nil, fmt.Errorf("failed to marshal signed tx: %w", err)
 		}
 		rawTxHex := "0x" + fmt.Sprintf("%x", rawTxBytes)
 
+		_, sendSpan := tracer.Start(txCtx, "send_raw_transaction_sync",
+			trace.WithAttributes(attribute.String("rpc.method", rpcMethod)))
 		sendStart := time.Now()
 		resultRaw, err := sendRawTransactionSyncWithMethod(rpcEndpoint, rawTxHex, rpcMethod)
 		sendEnd := time.Now()
+		endSpan(sendSpan, err)
 		if err != nil {
+			endSpan(txSpan, err)
+			observer.TxFailed(i+1, err)
 			// Log the error and continue with next transaction
-			log.Printf("[WARN] Tx %d: RPC call failed: %v. Skipping and continuing.", i+1, err)
+			Logger().Warn("RPC call failed, skipping and continuing", "tx", i+1, "err", err)
+			// The tx may still be included, so count its worst-case cost.
+			guard.record(signedTx, nil)
 			time.Sleep(2 * time.Second)
 		} else {
 			sendDuration := sendEnd.Sub(sendStart)
 
+			// The sync call returns the receipt, so confirmation is observed together with the send.
+			_, confirmSpan := tracer.Start(txCtx, "confirm")
 			var receipt types.Receipt
+			var blockNumber *big.Int
 			if err := json.Unmarshal(resultRaw, &receipt); err != nil {
 				// Log but continue
-				log.Printf("[WARN] Tx %d: failed to unmarshal receipt: %v", i+1, err)
+				Logger().Warn("failed to unmarshal receipt", "tx", i+1, "err", err)
+				endSpan(confirmSpan, err)
 			} else {
-				log.Printf("[INFO] Tx %d: receipt status: %d, block number: %d", i+1, receipt.Status, receipt.BlockNumber.Uint64())
+				Logger().Info("receipt received", "tx", i+1, "status", receipt.Status, "block", receipt.BlockNumber.Uint64())
+				blockNumber = receipt.BlockNumber
+				confirmSpan.SetAttributes(attribute.Int64("block.number", receipt.BlockNumber.Int64()))
+				confirmSpan.End()
 			}
+			txSpan.End()
 
-			txHash := signedTx.Hash()
-			log.Printf("[INFO] Tx %d: sent and received receipt for %s in %v", i+1, txHash.Hex(), sendDuration)
+			Logger().Info("tx sent and confirmed", "tx", i+1, "hash", txHash.Hex(), "send_time", sendDuration, "sign_time", signDuration)
 
-			results = append(results, Result{
+			result := Result{
 				TxIndex:     i + 1,
 				TxHash:      txHash.Hex(),
+				Warmup:      i < warmupTxs,
 				SendTime:    sendDuration.Milliseconds(),
 				ConfirmTime: 0,
 				TotalTime:   sendDuration.Milliseconds(),
-			})
+				SignTime:    signDuration.Microseconds(),
+				Outcome:     OutcomeIncluded,
+				Levels:      map[ConfirmationLevel]int64{syncLevel: sendDuration.Milliseconds()},
+			}
+			recordInclusion(ctx, client.Client(), &result, blockNumber, sendStart)
+			if result.Cost, err = parseTxCost(resultRaw); err != nil {
+				Logger().Warn("failed to read tx cost", "tx", i+1, "err", err)
+			}
+			guard.record(signedTx, result.Cost)
+			results = append(results, result)
+			observer.TxSent(i+1, result.TxHash)
+			observer.TxConfirmed(result)
 		}
 
 		nonce++
 	}
 
+	if dryRun {
+		reportDryRunPlan(rpcMethod, planned)
+	}
 	return results, nil
 }
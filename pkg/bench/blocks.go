@@ -0,0 +1,247 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// irregularIntervalFactor is how far (as a fraction of the median) a receive interval may deviate before
+// it is reported as irregular.
+const irregularIntervalFactor = 0.5
+
+// BlockObservation is a block header as seen by this client.
+type BlockObservation struct {
+	Number     uint64
+	Hash       string
+	Timestamp  uint64    // header timestamp, seconds
+	ReceivedAt time.Time // local time the block was first seen
+	GasUsed    uint64
+	GasLimit   uint64
+	TxCount    uint
+}
+
+// Drift returns how long after its header timestamp the block was seen locally.
+func (b BlockObservation) Drift() time.Duration {
+	return b.ReceivedAt.Sub(time.Unix(int64(b.Timestamp), 0))
+}
+
+// ObserveBlocks records every new block for duration, using a new-heads subscription on WebSocket
+// endpoints and polling eth_blockNumber every pollInterval otherwise.
+func ObserveBlocks(ctx context.Context, duration, pollInterval time.Duration) ([]BlockObservation, error) {
+	client, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	if strings.HasPrefix(rpcEndpoint, "ws") {
+		return subscribeBlocks(ctx, client)
+	}
+	return pollBlocks(ctx, client, pollInterval)
+}
+
+func subscribeBlocks(ctx context.Context, client *ethclient.Client) ([]BlockObservation, error) {
+	heads := make(chan *types.Header, 64)
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to new heads: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	var blocks []BlockObservation
+	for {
+		select {
+		case header := <-heads:
+			receivedAt := time.Now()
+			obs, err := observeHeader(ctx, client, header, receivedAt)
+			if err != nil {
+				// A head arriving as the duration ends cannot be fetched anymore; the run is still complete.
+				if ctx.Err() != nil {
+					return blocks, nil
+				}
+				return blocks, err
+			}
+			blocks = append(blocks, obs)
+		case err := <-sub.Err():
+			return blocks, fmt.Errorf("new heads subscription failed: %w", err)
+		case <-ctx.Done():
+			return blocks, nil
+		}
+	}
+}
+
+func pollBlocks(ctx context.Context, client *ethclient.Client, pollInterval time.Duration) ([]BlockObservation, error) {
+	latest, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	var blocks []BlockObservation
+	for {
+		select {
+		case <-ctx.Done():
+			return blocks, nil
+		case <-time.After(pollInterval):
+		}
+
+		number, err := client.BlockNumber(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return blocks, nil
+			}
+			Logger().Warn("failed to get block number", "err", err)
+			continue
+		}
+		receivedAt := time.Now()
+
+		// Blocks skipped between two polls share the receive time of the poll that revealed them.
+		for n := latest + 1; n <= number; n++ {
+			header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+			if err != nil {
+				if ctx.Err() != nil {
+					return blocks, nil
+				}
+				return blocks, fmt.Errorf("failed to get header %d: %w", n, err)
+			}
+			obs, err := observeHeader(ctx, client, header, receivedAt)
+			if err != nil {
+				if ctx.Err() != nil {
+					return blocks, nil
+				}
+				return blocks, err
+			}
+			blocks = append(blocks, obs)
+		}
+		if number > latest {
+			latest = number
+		}
+	}
+}
+
+func observeHeader(ctx context.Context, client *ethclient.Client, header *types.Header, receivedAt time.Time) (BlockObservation, error) {
+	txCount, err := client.TransactionCount(ctx, header.Hash())
+	if err != nil {
+		return BlockObservation{}, fmt.Errorf("failed to get tx count of block %d: %w", header.Number.Uint64(), err)
+	}
+	obs := BlockObservation{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash().Hex(),
+		Timestamp:  header.Time,
+		ReceivedAt: receivedAt,
+		GasUsed:    header.GasUsed,
+		GasLimit:   header.GasLimit,
+		TxCount:    txCount,
+	}
+	Logger().Debug("new block", "number", obs.Number, "txs", obs.TxCount, "gas_used", obs.GasUsed, "drift", obs.Drift())
+	return obs, nil
+}
+
+// BlockIntervals returns the local receive intervals between consecutive observed blocks, in milliseconds.
+// Blocks revealed by the same poll are skipped so that polling does not produce zero intervals.
+func BlockIntervals(blocks []BlockObservation) []int64 {
+	var intervals []int64
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].ReceivedAt.Equal(blocks[i-1].ReceivedAt) {
+			continue
+		}
+		intervals = append(intervals, blocks[i].ReceivedAt.Sub(blocks[i-1].ReceivedAt).Milliseconds())
+	}
+	return intervals
+}
+
+// PrintBlocksReport prints block cadence, utilisation and drift statistics for the observed blocks.
+func PrintBlocksReport(blocks []BlockObservation) {
+	if len(blocks) < 2 {
+		Logger().Warn("not enough blocks observed to report", "blocks", len(blocks))
+		return
+	}
+
+	first, last := blocks[0], blocks[len(blocks)-1]
+	produced := last.Number - first.Number + 1
+	fmt.Printf("\nObserved %d blocks (%d..%d), %d produced\n", len(blocks), first.Number, last.Number, produced)
+
+	var headerIntervals, drifts, gasUsed, txCounts []int64
+	var utilisation float64
+	for i, b := range blocks {
+		if i > 0 && b.Number == blocks[i-1].Number+1 {
+			headerIntervals = append(headerIntervals, int64(b.Timestamp-blocks[i-1].Timestamp)*1000)
+		}
+		drifts = append(drifts, b.Drift().Milliseconds())
+		gasUsed = append(gasUsed, int64(b.GasUsed))
+		txCounts = append(txCounts, int64(b.TxCount))
+		if b.GasLimit > 0 {
+			utilisation += float64(b.GasUsed) / float64(b.GasLimit)
+		}
+	}
+	receiveIntervals := BlockIntervals(blocks)
+
+	printRow := func(name string, values []int64) {
+		if len(values) == 0 {
+			fmt.Printf("%-24s %s\n", name, "-")
+			return
+		}
+		sorted := append([]int64(nil), values...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		var sum int64
+		for _, v := range sorted {
+			sum += v
+		}
+		fmt.Printf("%-24s %-12d %-12d %-12d %-12d %-12d %-12d\n", name,
+			sorted[0], sorted[len(sorted)-1], sum/int64(len(sorted)),
+			median(sorted), percentile(sorted, 90), percentile(sorted, 99))
+	}
+
+	fmt.Println("\nBLOCK STATISTICS:")
+	fmt.Printf("%-24s %-12s %-12s %-12s %-12s %-12s %-12s\n", "", "MIN", "MAX", "AVG", "MEDIAN", "P90", "P99")
+	fmt.Println("------------------------------------------------------------------------------------------------")
+	printRow("Receive interval (ms):", receiveIntervals)
+	printRow("Header interval (ms):", headerIntervals)
+	printRow("Receive drift (ms):", drifts)
+	printRow("Gas used:", gasUsed)
+	printRow("Tx count:", txCounts)
+	fmt.Printf("\nAverage gas utilisation: %.2f%% of a %d gas limit\n", utilisation/float64(len(blocks))*100, last.GasLimit)
+
+	if len(headerIntervals) > 0 {
+		// Header timestamps have second resolution, so a gap of at least twice the median
+		// (and at least 2s) means a slot was likely missed.
+		medHeader := median(headerIntervals)
+		missed := 0
+		for _, iv := range headerIntervals {
+			if iv >= max(2*medHeader, 2000) {
+				missed++
+			}
+		}
+		fmt.Printf("Missed slots (header interval >= 2x median): %d\n", missed)
+	}
+	if len(receiveIntervals) > 0 {
+		medReceive := median(receiveIntervals)
+		irregular := 0
+		for _, iv := range receiveIntervals {
+			if diff := iv - medReceive; float64(abs64(diff)) > irregularIntervalFactor*float64(medReceive) {
+				irregular++
+			}
+		}
+		fmt.Printf("Irregular receive intervals (>%.0f%% from the %d ms median): %d of %d\n",
+			irregularIntervalFactor*100, medReceive, irregular, len(receiveIntervals))
+	}
+	if skipped := int(produced) - len(blocks); skipped > 0 {
+		fmt.Printf("Blocks not observed individually: %d\n", skipped)
+	}
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
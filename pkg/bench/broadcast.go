@@ -0,0 +1,237 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BroadcastAttempt is the outcome of submitting a transaction to one endpoint of a broadcast.
+type BroadcastAttempt struct {
+	Endpoint     string
+	SendTime     int64 // milliseconds until the endpoint answered the send, -1 if it failed
+	AlreadyKnown bool  // the endpoint already had the tx from another endpoint; not a failure
+	Err          string
+	ReceiptTime  int64 // milliseconds from the broadcast start until this endpoint returned the receipt, -1 if never
+}
+
+type BroadcastResult struct {
+	TxIndex       int
+	TxHash        string
+	Attempts      []BroadcastAttempt
+	FirstAccepted int   // index into Attempts of the first endpoint to accept the tx, -1 if none did
+	FirstReceipt  int   // index into Attempts of the first endpoint to return the receipt, -1 if none did
+	ReceiptTime   int64 // milliseconds until the first receipt, -1 if none
+}
+
+// RunBroadcast sends each of txCount signed transactions to all endpoints concurrently and polls every
+// endpoint for the receipt, recording which endpoint accepted it and returned its receipt first. A tx no
+// endpoint accepted is recorded with FirstAccepted -1; unless an endpoint knew it or returned its receipt,
+// its nonce is reused. On an error, the results so far are returned with it.
+func RunBroadcast(txCount int, endpoints []string, pollInterval, timeout time.Duration) ([]BroadcastResult, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoints to broadcast to")
+	}
+	ctx := context.Background()
+
+	clients := make([]*ethclient.Client, len(endpoints))
+	for i, endpoint := range endpoints {
+		client, err := ethclient.Dial(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to RPC endpoint %s: %w", endpointLabel(endpoint), err)
+		}
+		defer client.Close()
+		clients[i] = client
+	}
+
+	chainID, err := getChainID(ctx, clients[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	signer := signers[0]
+	fromAddress := signer.Address()
+
+	nonce, err := clients[0].PendingNonceAt(ctx, fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	results := make([]BroadcastResult, 0, txCount)
+	for i := 0; i < txCount; i++ {
+		txCtx, txSpan := startTxSpan(ctx, "broadcast", i+1, nonce)
+
+		signedTx, err := signSelfTransfer(txCtx, clients[0], chainID, signer, nonce)
+		if err != nil {
+			endSpan(txSpan, err)
+			return results, err
+		}
+		txHash := signedTx.Hash()
+		txSpan.SetAttributes(attribute.String("tx.hash", txHash.Hex()))
+
+		watchCtx, cancel := context.WithTimeout(txCtx, timeout)
+		attempts := make([]BroadcastAttempt, len(clients))
+		var wg sync.WaitGroup
+
+		start := time.Now()
+		for j, client := range clients {
+			attempts[j] = BroadcastAttempt{Endpoint: endpointLabel(endpoints[j]), SendTime: -1, ReceiptTime: -1}
+			attempt := &attempts[j]
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				sendCtx, sendSpan := tracer.Start(txCtx, "send_transaction",
+					trace.WithAttributes(attribute.String("rpc.endpoint", attempt.Endpoint)))
+				err := client.SendTransaction(sendCtx, signedTx)
+				elapsed := time.Since(start)
+				switch {
+				case err == nil:
+					attempt.SendTime = elapsed.Milliseconds()
+				case isAlreadyKnown(err):
+					attempt.AlreadyKnown = true
+					err = nil
+				default:
+					attempt.Err = err.Error()
+				}
+				endSpan(sendSpan, err)
+
+				// Endpoints that rejected the tx may still learn about it from the others.
+				if _, _, err := pollReceipt(watchCtx, client, i+1, txHash, pollInterval); err == nil {
+					attempt.ReceiptTime = time.Since(start).Milliseconds()
+				}
+			}()
+		}
+		wg.Wait()
+		cancel()
+		txSpan.End()
+
+		result := BroadcastResult{
+			TxIndex:       i + 1,
+			TxHash:        txHash.Hex(),
+			Attempts:      attempts,
+			FirstAccepted: -1,
+			FirstReceipt:  -1,
+			ReceiptTime:   -1,
+		}
+		for j, attempt := range attempts {
+			if attempt.Err != "" {
+				Logger().Warn("broadcast send failed", "tx", i+1, "endpoint", attempt.Endpoint, "err", attempt.Err)
+			}
+			if attempt.SendTime >= 0 && (result.FirstAccepted < 0 || attempt.SendTime < attempts[result.FirstAccepted].SendTime) {
+				result.FirstAccepted = j
+			}
+			if attempt.ReceiptTime >= 0 && (result.FirstReceipt < 0 || attempt.ReceiptTime < attempts[result.FirstReceipt].ReceiptTime) {
+				result.FirstReceipt = j
+				result.ReceiptTime = attempt.ReceiptTime
+			}
+		}
+		results = append(results, result)
+		if result.FirstAccepted < 0 {
+			known := result.FirstReceipt >= 0
+			for _, attempt := range attempts {
+				known = known || attempt.AlreadyKnown
+			}
+			Logger().Warn("tx not accepted by any endpoint", "tx", i+1, "hash", txHash.Hex(), "known", known,
+				"receipt_ms", result.ReceiptTime)
+			// A tx every endpoint refused does not use its nonce, so the next tx reuses it.
+			if known {
+				nonce++
+			}
+			continue
+		}
+		Logger().Info("broadcast complete", "tx", i+1, "hash", txHash.Hex(),
+			"first_accepted", attempts[result.FirstAccepted].Endpoint, "receipt_ms", result.ReceiptTime)
+		nonce++
+	}
+
+	return results, nil
+}
+
+// isAlreadyKnown reports whether err is a node telling us it already has the transaction,
+// which is expected when the same tx is broadcast to several endpoints.
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"already known", "known transaction", "already imported", "already exists"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintBroadcastReport prints per-endpoint win counts and how far each endpoint's receipt lagged the first
+// receipt of the same broadcast. The lag compares endpoints within a broadcast; it is not the latency of
+// sending through one endpoint alone.
+func PrintBroadcastReport(results []BroadcastResult) {
+	if len(results) == 0 {
+		Logger().Warn("no results to report")
+		return
+	}
+
+	var fastest []int64
+	unaccepted := 0
+	for _, r := range results {
+		if r.ReceiptTime >= 0 {
+			fastest = append(fastest, r.ReceiptTime)
+		}
+		if r.FirstAccepted < 0 {
+			unaccepted++
+		}
+	}
+
+	fmt.Println("\nBROADCAST STATISTICS:")
+	fmt.Printf("%-24s %-9s %-7s %-7s %-10s %-10s %-12s %-12s\n",
+		"ENDPOINT", "ACCEPTED", "KNOWN", "FAILED", "1ST ACCEPT", "1ST RCPT", "MED RCPT", "MED LAG")
+	fmt.Println("------------------------------------------------------------------------------------------------")
+
+	for j := range results[0].Attempts {
+		var accepted, known, failed, firstAccepted, firstReceipt int
+		var receipts, lags []int64
+		for _, r := range results {
+			a := r.Attempts[j]
+			switch {
+			case a.SendTime >= 0:
+				accepted++
+			case a.AlreadyKnown:
+				known++
+			default:
+				failed++
+			}
+			if r.FirstAccepted == j {
+				firstAccepted++
+			}
+			if r.FirstReceipt == j {
+				firstReceipt++
+			}
+			if a.ReceiptTime >= 0 {
+				receipts = append(receipts, a.ReceiptTime)
+				// How long after the first endpoint of this broadcast this one returned the receipt.
+				lags = append(lags, a.ReceiptTime-r.ReceiptTime)
+			}
+		}
+
+		medReceipt, medLag := "-", "-"
+		if len(receipts) > 0 {
+			medReceipt = fmt.Sprintf("%d", median(receipts))
+			medLag = fmt.Sprintf("%d", median(lags))
+		}
+		fmt.Printf("%-24s %-9d %-7d %-7d %-10d %-10d %-12s %-12s\n",
+			results[0].Attempts[j].Endpoint, accepted, known, failed, firstAccepted, firstReceipt, medReceipt, medLag)
+	}
+	fmt.Println("MED LAG: median ms behind the first receipt of the same broadcast, not a single-endpoint send")
+	if unaccepted > 0 {
+		fmt.Printf("WARNING: %d of %d txs were not accepted by any endpoint\n", unaccepted, len(results))
+	}
+
+	if len(fastest) > 0 {
+		fmt.Printf("\nMedian time to first receipt across all endpoints (ms): %d\n", median(fastest))
+	}
+}
@@ -0,0 +1,189 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// msTimestampFields are extra header fields some chains use to expose a millisecond block timestamp.
+var msTimestampFields = []string{"timestampMs", "milliTimestamp", "timestampMillis"}
+
+// clockSampleTimeout bounds how long EstimateClockOffset waits for new heads.
+const clockSampleTimeout = time.Minute
+
+// blockTimestampMs returns the timestamp of block number (nil for latest) in unix milliseconds. precise
+// reports whether the chain exposed a millisecond timestamp; otherwise the second-resolution header
+// timestamp is used.
+func blockTimestampMs(ctx context.Context, client *rpc.Client, number *big.Int) (ts int64, precise bool, err error) {
+	tag := "latest"
+	if number != nil {
+		tag = hexutil.EncodeBig(number)
+	}
+	var header map[string]json.RawMessage
+	if err := client.CallContext(ctx, &header, "eth_getBlockByNumber", tag, false); err != nil {
+		return 0, false, fmt.Errorf("failed to get block %s: %w", tag, err)
+	}
+	if header == nil {
+		return 0, false, fmt.Errorf("block %s not found", tag)
+	}
+
+	for _, field := range msTimestampFields {
+		if raw, ok := header[field]; ok {
+			if ms, err := parseQuantity(raw); err == nil {
+				return int64(ms), true, nil
+			}
+		}
+	}
+	secs, err := parseQuantity(header["timestamp"])
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid timestamp of block %s: %w", tag, err)
+	}
+	return int64(secs) * 1000, false, nil
+}
+
+// parseQuantity parses a hex quantity, a decimal string or a JSON number.
+func parseQuantity(raw json.RawMessage) (uint64, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var n uint64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return 0, fmt.Errorf("not a quantity: %s", raw)
+		}
+		return n, nil
+	}
+	if len(s) > 2 && s[:2] == "0x" {
+		return hexutil.DecodeUint64(s)
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// ClockOffset is the estimated offset of the local clock against block timestamps.
+type ClockOffset struct {
+	Offset  time.Duration // local clock minus chain clock
+	Samples int
+	Precise bool // block timestamps had millisecond resolution
+}
+
+// EstimateClockOffset observes samples new heads, polling every pollInterval, and estimates the local
+// clock offset as the smallest gap between a head's local receive time and its timestamp. That gap also
+// contains propagation and polling delay, and truncation on chains with second-resolution timestamps,
+// so the estimate is an upper bound that tightens with more samples.
+func EstimateClockOffset(ctx context.Context, client *rpc.Client, samples int, pollInterval time.Duration) (ClockOffset, error) {
+	ctx, cancel := context.WithTimeout(ctx, clockSampleTimeout)
+	defer cancel()
+
+	var latest hexutil.Uint64
+	if err := client.CallContext(ctx, &latest, "eth_blockNumber"); err != nil {
+		return ClockOffset{}, fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	est := ClockOffset{Precise: true}
+	for est.Samples < samples {
+		select {
+		case <-ctx.Done():
+			if est.Samples == 0 {
+				return est, fmt.Errorf("no new heads observed within %v", clockSampleTimeout)
+			}
+			Logger().Warn("clock offset estimated from fewer heads than requested", "samples", est.Samples, "requested", samples)
+			return est, nil
+		case <-time.After(pollInterval):
+		}
+
+		var number hexutil.Uint64
+		if err := client.CallContext(ctx, &number, "eth_blockNumber"); err != nil {
+			Logger().Warn("failed to get block number", "err", err)
+			continue
+		}
+		receivedAt := time.Now()
+		if number <= latest {
+			continue
+		}
+		latest = number
+
+		ts, precise, err := blockTimestampMs(ctx, client, new(big.Int).SetUint64(uint64(number)))
+		if err != nil {
+			Logger().Warn("failed to get block timestamp", "block", uint64(number), "err", err)
+			continue
+		}
+		drift := time.Duration(receivedAt.UnixMilli()-ts) * time.Millisecond
+		if est.Samples == 0 || drift < est.Offset {
+			est.Offset = drift
+		}
+		est.Precise = est.Precise && precise
+		est.Samples++
+		Logger().Debug("head observed", "block", uint64(number), "drift", drift)
+	}
+	return est, nil
+}
+
+// CorrectClockSkew fills the offset-corrected chain latency of results that have an inclusion timestamp.
+func CorrectClockSkew(results []Result, offset ClockOffset) {
+	for i := range results {
+		if results[i].BlockTimestamp == 0 {
+			continue
+		}
+		results[i].ChainLatencyCorrected = results[i].ChainLatency + offset.Offset.Milliseconds()
+	}
+}
+
+// PrintClockReport prints send-to-inclusion latency measured against block timestamps, raw and corrected for
+// the estimated clock offset.
+func PrintClockReport(results []Result, offset ClockOffset) {
+	var raw, corrected []int64
+	for _, r := range results {
+		if r.BlockTimestamp == 0 {
+			continue
+		}
+		raw = append(raw, r.ChainLatency)
+		corrected = append(corrected, r.ChainLatencyCorrected)
+	}
+	if len(raw) == 0 {
+		Logger().Warn("no inclusion timestamps to report")
+		return
+	}
+
+	resolution := "1s"
+	if offset.Precise {
+		resolution = "1ms"
+	}
+	fmt.Printf("\nClock offset (local - chain): %d ms, estimated from %d heads, timestamp resolution %s\n",
+		offset.Offset.Milliseconds(), offset.Samples, resolution)
+
+	fmt.Println("\nSEND TO INCLUSION (block timestamp - local send time):")
+	fmt.Printf("%-13s %-10s %-10s %-10s %-10s %-10s\n", "", "MIN (ms)", "MAX (ms)", "MEDIAN (ms)", "P90 (ms)", "P99 (ms)")
+	fmt.Println("--------------------------------------------------------------------")
+	for _, row := range []struct {
+		name   string
+		values []int64
+	}{{"Raw:", raw}, {"Corrected:", corrected}} {
+		sorted := append([]int64(nil), row.values...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		fmt.Printf("%-13s %-10d %-10d %-11d %-10d %-10d\n", row.name,
+			sorted[0], sorted[len(sorted)-1], median(sorted), percentile(sorted, 90), percentile(sorted, 99))
+	}
+}
+
+// recordInclusion sets the inclusion block and its timing relative to sentAt on result. Failures are logged
+// and leave the server-side timing empty, since they do not affect the local measurements.
+func recordInclusion(ctx context.Context, client *rpc.Client, result *Result, blockNumber *big.Int, sentAt time.Time) {
+	result.SentAt = sentAt.UnixMilli()
+	if blockNumber == nil {
+		return
+	}
+	result.BlockNumber = blockNumber.Uint64()
+	ts, _, err := blockTimestampMs(ctx, client, blockNumber)
+	if err != nil {
+		Logger().Warn("failed to get inclusion block timestamp", "tx", result.TxIndex, "err", err)
+		return
+	}
+	result.BlockTimestamp = ts
+	result.ChainLatency = ts - result.SentAt
+}
@@ -0,0 +1,301 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	maxSpend *big.Int
+	spend    = newSpendGuard()
+)
+
+// SetSpendLimit makes subsequent runs stop before sending a transaction that could take the fees paid
+// above limit wei. The limit covers all runs until the next call, so the two runs of a comparison share
+// it. A nil limit disables the guard.
+func SetSpendLimit(limit *big.Int) {
+	maxSpend = limit
+	spend = newSpendGuard()
+}
+
+// Fee models of the chains whose receipts report a data-availability fee.
+const (
+	FeeModelStandard = "standard" // execution fee only
+	FeeModelOPStack  = "op-stack" // separate L1 data fee on top of the execution fee (l1Fee)
+	FeeModelArbitrum = "arbitrum" // L1 data fee charged as extra L2 gas (gasUsedForL1)
+)
+
+// TxCost is the fee paid for a transaction, taken from its receipt.
+type TxCost struct {
+	FeeModel          string
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	ExecutionFee      *big.Int // fee for L2 execution
+	L1Fee             *big.Int // data-availability fee paid for posting the tx to L1, zero on L1 chains
+	// BlobFee is the part of L1Fee priced by the L1 blob base fee (OP-stack since Ecotone); the rest
+	// pays for calldata.
+	BlobFee *big.Int
+	// L1GasUsed is the L1 gas the data fee was computed from (OP-stack l1GasUsed), or the L2 gas charged
+	// for it (Arbitrum gasUsedForL1).
+	L1GasUsed uint64
+}
+
+// Total returns the total fee paid in wei.
+func (c *TxCost) Total() *big.Int {
+	return new(big.Int).Add(c.ExecutionFee, c.L1Fee)
+}
+
+// parseTxCost reads the fee fields of a raw JSON receipt, including the OP-stack and Arbitrum extensions.
+func parseTxCost(raw json.RawMessage) (*TxCost, error) {
+	var receipt struct {
+		GasUsed           *hexutil.Uint64 `json:"gasUsed"`
+		EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+
+		// OP-stack
+		L1Fee               *hexutil.Big    `json:"l1Fee"`
+		L1GasUsed           *hexutil.Uint64 `json:"l1GasUsed"`
+		L1GasPrice          *hexutil.Big    `json:"l1GasPrice"`
+		L1BaseFeeScalar     *hexutil.Big    `json:"l1BaseFeeScalar"`
+		L1BlobBaseFee       *hexutil.Big    `json:"l1BlobBaseFee"`
+		L1BlobBaseFeeScalar *hexutil.Big    `json:"l1BlobBaseFeeScalar"`
+
+		// Arbitrum
+		GasUsedForL1 *hexutil.Uint64 `json:"gasUsedForL1"`
+	}
+	if err := json.Unmarshal(raw, &receipt); err != nil {
+		return nil, fmt.Errorf("invalid receipt: %w", err)
+	}
+	if receipt.GasUsed == nil || receipt.EffectiveGasPrice == nil {
+		return nil, fmt.Errorf("receipt has no gasUsed or effectiveGasPrice")
+	}
+
+	cost := &TxCost{
+		FeeModel:          FeeModelStandard,
+		GasUsed:           uint64(*receipt.GasUsed),
+		EffectiveGasPrice: receipt.EffectiveGasPrice.ToInt(),
+		L1Fee:             new(big.Int),
+		BlobFee:           new(big.Int),
+	}
+	executionGas := cost.GasUsed
+	switch {
+	case receipt.L1Fee != nil:
+		cost.FeeModel = FeeModelOPStack
+		cost.L1Fee = receipt.L1Fee.ToInt()
+		if receipt.L1GasUsed != nil {
+			cost.L1GasUsed = uint64(*receipt.L1GasUsed)
+		}
+		// Since Ecotone the L1 fee is l1GasUsed × (16 × l1BaseFee × baseScalar + blobBaseFee × blobScalar) / 16e6,
+		// so it splits between calldata and blobs in proportion to the two weighted prices.
+		if receipt.L1GasPrice != nil && receipt.L1BaseFeeScalar != nil && receipt.L1BlobBaseFee != nil && receipt.L1BlobBaseFeeScalar != nil {
+			calldata := new(big.Int).Mul(receipt.L1GasPrice.ToInt(), receipt.L1BaseFeeScalar.ToInt())
+			calldata.Mul(calldata, big.NewInt(16))
+			blob := new(big.Int).Mul(receipt.L1BlobBaseFee.ToInt(), receipt.L1BlobBaseFeeScalar.ToInt())
+			if weight := new(big.Int).Add(calldata, blob); weight.Sign() > 0 {
+				cost.BlobFee = new(big.Int).Mul(cost.L1Fee, blob)
+				cost.BlobFee.Div(cost.BlobFee, weight)
+			}
+		}
+	case receipt.GasUsedForL1 != nil:
+		// Arbitrum includes the L1 data cost in gasUsed, so the data fee is carved out of it.
+		cost.FeeModel = FeeModelArbitrum
+		cost.L1GasUsed = min(uint64(*receipt.GasUsedForL1), cost.GasUsed)
+		executionGas -= cost.L1GasUsed
+		cost.L1Fee = new(big.Int).Mul(cost.EffectiveGasPrice, new(big.Int).SetUint64(cost.L1GasUsed))
+	}
+	cost.ExecutionFee = new(big.Int).Mul(cost.EffectiveGasPrice, new(big.Int).SetUint64(executionGas))
+	return cost, nil
+}
+
+// fetchTxCost fetches the receipt of txHash and returns the fee paid.
+func fetchTxCost(ctx context.Context, client *rpc.Client, txHash common.Hash) (*TxCost, error) {
+	var raw json.RawMessage
+	if err := client.CallContext(ctx, &raw, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+	if !hasResult(raw) {
+		return nil, fmt.Errorf("receipt of %s not found", txHash.Hex())
+	}
+	return parseTxCost(raw)
+}
+
+// gasPriceOracle is the OP-stack predeploy that prices the L1 data fee of a transaction.
+var gasPriceOracle = common.HexToAddress("0x420000000000000000000000000000000000000F")
+
+// l1FeeHeadroom multiplies the quoted L1 data fee when balance is reserved for it, as the L1 base fee
+// may rise before the transaction is included.
+const l1FeeHeadroom = 2
+
+// reserveL1Fee returns the L1 data fee to hold back for the signed tx: the GasPriceOracle quote times
+// l1FeeHeadroom. It is 0 on chains without the oracle, where the txpool checks the execution fee only.
+func reserveL1Fee(ctx context.Context, client *ethclient.Client, tx *types.Transaction) (*big.Int, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal signed tx: %w", err)
+	}
+	// ABI encoding of getL1Fee(bytes): selector, offset of the argument, its length and the padded bytes.
+	data := crypto.Keccak256([]byte("getL1Fee(bytes)"))[:4]
+	data = append(data, common.LeftPadBytes(big.NewInt(32).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(raw))).Bytes(), 32)...)
+	data = append(data, common.RightPadBytes(raw, (len(raw)+31)/32*32)...)
+
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &gasPriceOracle, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L1 fee: %w", err)
+	}
+	if len(out) < 32 {
+		return new(big.Int), nil
+	}
+	fee := new(big.Int).SetBytes(out[:32])
+	return fee.Mul(fee, big.NewInt(l1FeeHeadroom)), nil
+}
+
+// spendGuard tracks the fees paid since SetSpendLimit against maxSpend.
+type spendGuard struct {
+	limit    *big.Int
+	spent    *big.Int
+	maxL1Fee *big.Int // largest OP-stack L1 data fee seen, used as the estimate for the next tx
+}
+
+func newSpendGuard() *spendGuard {
+	return &spendGuard{limit: maxSpend, spent: new(big.Int), maxL1Fee: new(big.Int)}
+}
+
+// allow returns an error if sending tx could take the fees paid above the limit.
+func (g *spendGuard) allow(tx *types.Transaction) error {
+	if g.limit == nil {
+		return nil
+	}
+	worst := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+	worst.Add(worst, g.maxL1Fee).Add(worst, g.spent)
+	if worst.Cmp(g.limit) > 0 {
+		return fmt.Errorf("spent %s ETH, next tx could cost up to %s ETH more, exceeding the %s ETH limit",
+			FormatEther(g.spent), FormatEther(new(big.Int).Sub(worst, g.spent)), FormatEther(g.limit))
+	}
+	return nil
+}
+
+// record adds the fee paid for a confirmed transaction. Without a known cost the worst case of tx is
+// counted instead.
+func (g *spendGuard) record(tx *types.Transaction, cost *TxCost) {
+	if cost == nil {
+		g.spent.Add(g.spent, new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap()))
+		g.spent.Add(g.spent, g.maxL1Fee)
+		return
+	}
+	g.spent.Add(g.spent, cost.Total())
+	// Only the OP-stack charges the L1 fee on top of gas. On Arbitrum it is part of gasUsed, which the
+	// worst case of the next tx already bounds with its gas limit and fee cap.
+	if cost.FeeModel == FeeModelOPStack && cost.L1Fee.Cmp(g.maxL1Fee) > 0 {
+		g.maxL1Fee.Set(cost.L1Fee)
+	}
+}
+
+// CostEstimate is the expected cost of a run from the current fee data, excluding any L1 data fee.
+type CostEstimate struct {
+	Txs      int
+	Expected *big.Int // at the suggested gas price
+	Max      *big.Int // at the fee cap the runners set
+}
+
+// EstimateCost estimates the cost of sending txCount benchmark transactions at the current fees.
+func EstimateCost(ctx context.Context, client *ethclient.Client, txCount int) (CostEstimate, error) {
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return CostEstimate{}, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	gas := new(big.Int).SetUint64(selfTransferGas * uint64(txCount))
+	return CostEstimate{
+		Txs:      txCount,
+		Expected: new(big.Int).Mul(gasPrice, gas),
+		Max:      new(big.Int).Mul(new(big.Int).Mul(gasPrice, big.NewInt(2)), gas),
+	}, nil
+}
+
+// PrintCostEstimate prints the pre-run cost estimate.
+func PrintCostEstimate(est CostEstimate) {
+	fmt.Printf("\nEstimated cost of %d txs: %s ETH expected, %s ETH at most (excluding L1 data fees)\n",
+		est.Txs, FormatEther(est.Expected), FormatEther(est.Max))
+	if maxSpend != nil && est.Max.Cmp(maxSpend) > 0 {
+		fmt.Printf("The run may stop early at the %s ETH spend limit\n", FormatEther(maxSpend))
+	}
+}
+
+// PrintCostReport prints the fees actually paid by the confirmed transactions, split into execution and
+// data-availability parts.
+func PrintCostReport(results []Result) {
+	total, execution, l1, blob := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	var gasUsed, l1GasUsed uint64
+	models := make(map[string]int)
+	txs := 0
+	for _, r := range results {
+		if r.Cost == nil {
+			continue
+		}
+		txs++
+		models[r.Cost.FeeModel]++
+		gasUsed += r.Cost.GasUsed
+		l1GasUsed += r.Cost.L1GasUsed
+		execution.Add(execution, r.Cost.ExecutionFee)
+		l1.Add(l1, r.Cost.L1Fee)
+		blob.Add(blob, r.Cost.BlobFee)
+		total.Add(total, r.Cost.Total())
+	}
+	if txs == 0 {
+		return
+	}
+
+	var names []string
+	for model, n := range models {
+		names = append(names, fmt.Sprintf("%s: %d", model, n))
+	}
+	sort.Strings(names)
+
+	perTx := func(v *big.Int) string {
+		return FormatEther(new(big.Int).Div(v, big.NewInt(int64(txs))))
+	}
+	share := func(v *big.Int) float64 {
+		if total.Sign() == 0 {
+			return 0
+		}
+		f, _ := new(big.Rat).SetFrac(v, total).Float64()
+		return 100 * f
+	}
+
+	fmt.Printf("\nCOST (%d txs with receipts; fee model %s):\n", txs, strings.Join(names, ", "))
+	fmt.Printf("%-18s %-26s %-26s %-8s %s\n", "FEE", "TOTAL (ETH)", "PER TX (ETH)", "SHARE", "GAS")
+	fmt.Println("--------------------------------------------------------------------------------------------")
+	fmt.Printf("%-18s %-26s %-26s %-8s %d\n", "Execution", FormatEther(execution), perTx(execution),
+		fmt.Sprintf("%.1f%%", share(execution)), gasUsed-arbitrumL1Gas(results))
+	fmt.Printf("%-18s %-26s %-26s %-8s %d\n", "Data availability", FormatEther(l1), perTx(l1),
+		fmt.Sprintf("%.1f%%", share(l1)), l1GasUsed)
+	if blob.Sign() > 0 {
+		calldata := new(big.Int).Sub(l1, blob)
+		fmt.Printf("%-18s %-26s %-26s %-8s\n", "  calldata", FormatEther(calldata), perTx(calldata),
+			fmt.Sprintf("%.1f%%", share(calldata)))
+		fmt.Printf("%-18s %-26s %-26s %-8s\n", "  blob", FormatEther(blob), perTx(blob), fmt.Sprintf("%.1f%%", share(blob)))
+	}
+	fmt.Printf("%-18s %-26s %-26s\n", "Total", FormatEther(total), perTx(total))
+}
+
+// arbitrumL1Gas sums the L2 gas Arbitrum charged for L1 data, which its receipts include in gasUsed.
+func arbitrumL1Gas(results []Result) uint64 {
+	var gas uint64
+	for _, r := range results {
+		if r.Cost != nil && r.Cost.FeeModel == FeeModelArbitrum {
+			gas += r.Cost.L1GasUsed
+		}
+	}
+	return gas
+}
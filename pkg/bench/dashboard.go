@@ -0,0 +1,202 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dashboardRefresh   = 250 * time.Millisecond
+	dashboardTPSWindow = 10 * time.Second
+	sparklineWidth     = 60
+	recentHashCount    = 8
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Dashboard renders live benchmark progress to a terminal. It implements Observer.
+type Dashboard struct {
+	out   io.Writer
+	total int
+
+	mu          sync.Mutex
+	start       time.Time
+	sent        int
+	confirmed   int
+	failed      int
+	pollErrors  int
+	lastErr     string
+	totals      []int64
+	confirmedAt []time.Time
+	recent      []string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewDashboard creates a dashboard for a run of total transactions writing to out.
+func NewDashboard(total int, out io.Writer) *Dashboard {
+	return &Dashboard{
+		out:   out,
+		total: total,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// Start begins redrawing the dashboard periodically until Stop is called.
+func (d *Dashboard) Start() {
+	d.mu.Lock()
+	d.start = time.Now()
+	d.mu.Unlock()
+
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(dashboardRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				d.render()
+			case <-d.stop:
+				d.render()
+				return
+			}
+		}
+	}()
+}
+
+// Stop draws the final frame and stops redrawing.
+func (d *Dashboard) Stop() {
+	close(d.stop)
+	<-d.done
+}
+
+func (d *Dashboard) TxSent(txIndex int, txHash string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sent++
+	d.recent = append(d.recent, fmt.Sprintf("#%-5d %s", txIndex, txHash))
+	if len(d.recent) > recentHashCount {
+		d.recent = d.recent[len(d.recent)-recentHashCount:]
+	}
+}
+
+func (d *Dashboard) TxConfirmed(r Result) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.confirmed++
+	d.totals = append(d.totals, r.TotalTime)
+	d.confirmedAt = append(d.confirmedAt, time.Now())
+}
+
+func (d *Dashboard) TxFailed(txIndex int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failed++
+	d.lastErr = fmt.Sprintf("tx %d: %v", txIndex, err)
+}
+
+func (d *Dashboard) PollFailed(txIndex int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pollErrors++
+	d.lastErr = fmt.Sprintf("tx %d receipt poll: %v", txIndex, err)
+}
+
+// currentTPS returns the confirmation rate over the trailing window.
+func (d *Dashboard) currentTPS(now time.Time) float64 {
+	window := dashboardTPSWindow
+	if elapsed := now.Sub(d.start); elapsed < window {
+		window = elapsed
+	}
+	if window <= 0 {
+		return 0
+	}
+	cutoff := now.Add(-window)
+	idx := sort.Search(len(d.confirmedAt), func(i int) bool { return d.confirmedAt[i].After(cutoff) })
+	return float64(len(d.confirmedAt)-idx) / window.Seconds()
+}
+
+func (d *Dashboard) render() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	var b strings.Builder
+
+	// Move cursor home and clear the screen.
+	b.WriteString("\033[H\033[2J")
+	fmt.Fprintf(&b, "EVM LATENCY BENCH  elapsed %v\n\n", now.Sub(d.start).Truncate(time.Second))
+
+	fmt.Fprintf(&b, "Progress:  %s %d/%d\n", progressBar(d.confirmed+d.failed, d.total, 40), d.confirmed+d.failed, d.total)
+	fmt.Fprintf(&b, "Sent: %-6d Confirmed: %-6d Failed: %-6d Poll errors: %-6d\n", d.sent, d.confirmed, d.failed, d.pollErrors)
+	fmt.Fprintf(&b, "TPS (last %v): %.2f\n\n", dashboardTPSWindow, d.currentTPS(now))
+
+	b.WriteString("Total latency (ms):\n")
+	if len(d.totals) > 0 {
+		sorted := make([]int64, len(d.totals))
+		copy(sorted, d.totals)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		fmt.Fprintf(&b, "  last %-6d p50 %-6d p90 %-6d p99 %-6d max %-6d\n",
+			d.totals[len(d.totals)-1],
+			percentile(sorted, 50), percentile(sorted, 90), percentile(sorted, 99),
+			sorted[len(sorted)-1],
+		)
+		fmt.Fprintf(&b, "  %s\n\n", sparkline(d.totals, sparklineWidth))
+	} else {
+		b.WriteString("  waiting for first confirmation...\n\n")
+	}
+
+	b.WriteString("Recent transactions:\n")
+	for i := len(d.recent) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "  %s\n", d.recent[i])
+	}
+
+	if d.lastErr != "" {
+		fmt.Fprintf(&b, "\nLast error: %s\n", d.lastErr)
+	}
+
+	_, _ = io.WriteString(d.out, b.String())
+}
+
+// progressBar renders done/total as a fixed-width bar.
+func progressBar(done, total, width int) string {
+	if total <= 0 {
+		return "[" + strings.Repeat(" ", width) + "]"
+	}
+	filled := done * width / total
+	if filled > width {
+		filled = width
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(" ", width-filled) + "]"
+}
+
+// sparkline renders the last width values scaled between their min and max.
+func sparkline(values []int64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) * int64(len(sparkBlocks)-1) / (hi - lo))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
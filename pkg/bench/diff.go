@@ -0,0 +1,236 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Metric is a per-transaction latency of Result.
+type Metric string
+
+const (
+	MetricSend    Metric = "send"
+	MetricConfirm Metric = "confirm"
+	MetricTotal   Metric = "total"
+)
+
+// LatencyMetrics are the metrics compared between runs.
+var LatencyMetrics = []Metric{MetricSend, MetricConfirm, MetricTotal}
+
+// diffPercentiles are the percentiles reported by a run comparison.
+var diffPercentiles = []float64{50, 75, 90, 95, 99}
+
+// ParseMetric parses a metric name.
+func ParseMetric(s string) (Metric, error) {
+	switch m := Metric(s); m {
+	case MetricSend, MetricConfirm, MetricTotal:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown metric %q, must be 'send', 'confirm' or 'total'", s)
+	}
+}
+
+// value returns the metric of r in milliseconds.
+func (m Metric) value(r Result) int64 {
+	switch m {
+	case MetricSend:
+		return r.SendTime
+	case MetricConfirm:
+		return r.ConfirmTime
+	default:
+		return r.TotalTime
+	}
+}
+
+// MetricValues returns metric of every result, sorted ascending.
+func MetricValues(results []Result, metric Metric) []int64 {
+	values := make([]int64, len(results))
+	for i, r := range results {
+		values[i] = metric.value(r)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+// PercentileDelta compares one percentile of two runs.
+type PercentileDelta struct {
+	Percentile float64
+	A, B       int64   // milliseconds
+	Delta      int64   // B minus A
+	DeltaPct   float64 // Delta relative to A, in percent
+}
+
+// MetricDiff is the comparison of one metric between run A and run B.
+type MetricDiff struct {
+	Metric      Metric
+	SamplesA    int
+	SamplesB    int
+	Percentiles []PercentileDelta
+	// U and PValue are the Mann-Whitney U statistic of A and its two-sided p-value.
+	U      float64
+	PValue float64
+	// MedianDelta is the median of B minus the median of A, with its bootstrap confidence interval.
+	MedianDelta float64
+	CILow       float64
+	CIHigh      float64
+	Significant bool
+	Verdict     string // "faster", "slower" or "no significant difference", for B relative to A
+	Confidence  float64
+	Resamples   int
+}
+
+// DiffRuns compares every latency metric of run b against run a. A difference is significant when the
+// Mann-Whitney U test rejects equal distributions at confidence and the bootstrap confidence interval of
+// the median difference, from bootstrap resamples, excludes zero.
+func DiffRuns(a, b []Result, confidence float64, bootstrap int) ([]MetricDiff, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return nil, fmt.Errorf("both runs need results")
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, fmt.Errorf("confidence must be between 0 and 1, got %g", confidence)
+	}
+	if bootstrap < 100 {
+		return nil, fmt.Errorf("at least 100 bootstrap resamples are needed, got %d", bootstrap)
+	}
+
+	// A fixed seed keeps the interval reproducible for the same pair of files.
+	rng := rand.New(rand.NewSource(1))
+	diffs := make([]MetricDiff, 0, len(LatencyMetrics))
+	for _, metric := range LatencyMetrics {
+		va, vb := MetricValues(a, metric), MetricValues(b, metric)
+		d := MetricDiff{
+			Metric:     metric,
+			SamplesA:   len(va),
+			SamplesB:   len(vb),
+			Confidence: confidence,
+			Resamples:  bootstrap,
+		}
+		for _, p := range diffPercentiles {
+			pa, pb := percentile(va, p), percentile(vb, p)
+			pd := PercentileDelta{Percentile: p, A: pa, B: pb, Delta: pb - pa}
+			if pa != 0 {
+				pd.DeltaPct = 100 * float64(pd.Delta) / float64(pa)
+			}
+			d.Percentiles = append(d.Percentiles, pd)
+		}
+
+		d.U, d.PValue = mannWhitneyU(va, vb)
+		d.MedianDelta = medianFloat(vb) - medianFloat(va)
+		d.CILow, d.CIHigh = bootstrapMedianDelta(rng, va, vb, confidence, bootstrap)
+
+		alpha := 1 - confidence
+		d.Significant = d.PValue < alpha && (d.CILow > 0 || d.CIHigh < 0)
+		switch {
+		case !d.Significant:
+			d.Verdict = "no significant difference"
+		case d.MedianDelta < 0:
+			d.Verdict = "faster"
+		default:
+			d.Verdict = "slower"
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs, nil
+}
+
+// mannWhitneyU returns the U statistic of a and the two-sided p-value from the normal approximation with
+// tie and continuity corrections.
+func mannWhitneyU(a, b []int64) (u, p float64) {
+	type sample struct {
+		v     int64
+		fromA bool
+	}
+	all := make([]sample, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range b {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	var rankSumA, tieTerm float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // average of ranks i+1..j
+		for k := i; k < j; k++ {
+			if all[k].fromA {
+				rankSumA += rank
+			}
+		}
+		t := float64(j - i)
+		tieTerm += t*t*t - t
+		i = j
+	}
+
+	n1, n2 := float64(len(a)), float64(len(b))
+	n := n1 + n2
+	u = rankSumA - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return u, 1
+	}
+	z := math.Max(math.Abs(u-mean)-0.5, 0) / math.Sqrt(variance)
+	return u, math.Erfc(z / math.Sqrt2)
+}
+
+// bootstrapMedianDelta returns the percentile bootstrap confidence interval of median(b) - median(a).
+func bootstrapMedianDelta(rng *rand.Rand, a, b []int64, confidence float64, iterations int) (low, high float64) {
+	ra, rb := make([]int64, len(a)), make([]int64, len(b))
+	deltas := make([]float64, iterations)
+	for i := range deltas {
+		for j := range ra {
+			ra[j] = a[rng.Intn(len(a))]
+		}
+		for j := range rb {
+			rb[j] = b[rng.Intn(len(b))]
+		}
+		deltas[i] = medianFloat(rb) - medianFloat(ra)
+	}
+	sort.Float64s(deltas)
+	alpha := 1 - confidence
+	lowIdx := int(math.Floor(alpha / 2 * float64(iterations)))
+	highIdx := min(int(math.Ceil((1-alpha/2)*float64(iterations)))-1, iterations-1)
+	return deltas[lowIdx], deltas[highIdx]
+}
+
+// medianFloat returns the median of values, averaging the two middle values of an even count.
+func medianFloat(values []int64) float64 {
+	sorted := make([]int64, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+	return float64(sorted[mid])
+}
+
+// PrintDiffReport prints the per-percentile deltas and the significance verdict of each metric.
+func PrintDiffReport(a, b RunFile, diffs []MetricDiff) {
+	fmt.Printf("\nRUN DIFF: A = %s %s (%s), B = %s %s (%s)\n",
+		a.Mode, a.Endpoint, a.StartedAt.Format("2006-01-02 15:04"), b.Mode, b.Endpoint, b.StartedAt.Format("2006-01-02 15:04"))
+	for _, d := range diffs {
+		fmt.Printf("\n%s time (A: %d txs, B: %d txs):\n", d.Metric, d.SamplesA, d.SamplesB)
+		fmt.Printf("%-6s %-10s %-10s %-12s %-10s\n", "PCT", "A (ms)", "B (ms)", "DELTA (ms)", "DELTA (%)")
+		fmt.Println("---------------------------------------------------")
+		for _, p := range d.Percentiles {
+			fmt.Printf("%-6s %-10d %-10d %-12d %-10s\n", fmt.Sprintf("p%g", p.Percentile), p.A, p.B, p.Delta,
+				fmt.Sprintf("%+.1f%%", p.DeltaPct))
+		}
+		fmt.Printf("Mann-Whitney U = %.1f, p = %.4f\n", d.U, d.PValue)
+		fmt.Printf("Median delta: %+.1f ms, %g%% bootstrap CI [%+.1f, %+.1f] (%d resamples)\n",
+			d.MedianDelta, 100*d.Confidence, d.CILow, d.CIHigh, d.Resamples)
+		if d.Significant {
+			fmt.Printf("Verdict: B is %s than A\n", d.Verdict)
+		} else {
+			fmt.Printf("Verdict: %s\n", d.Verdict)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package bench
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMannWhitneyU checks U and the tie- and continuity-corrected normal p-value against reference
+// values computed by counting pairs, as R's wilcox.test(exact = FALSE, correct = TRUE) does.
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int64
+		u, p float64
+	}{
+		{"ties across samples", []int64{1, 2, 2, 3, 4, 4, 5}, []int64{3, 4, 4, 5, 6, 6, 7, 8}, 8, 0.0222873190},
+		{"identical samples", []int64{10, 20, 20, 30}, []int64{10, 20, 20, 30}, 8, 1},
+		{"separated with ties", []int64{5, 5, 5, 5, 6}, []int64{7, 7, 8, 8, 8, 9}, 0, 0.0060979138},
+		{"all equal", []int64{3, 3, 3}, []int64{3, 3}, 3, 1},
+	}
+	for _, tt := range tests {
+		u, p := mannWhitneyU(tt.a, tt.b)
+		if u != tt.u {
+			t.Errorf("%s: got U %g, want %g", tt.name, u, tt.u)
+		}
+		if math.Abs(p-tt.p) > 1e-9 {
+			t.Errorf("%s: got p %.10f, want %.10f", tt.name, p, tt.p)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TipLevel is a priority fee expressed as a multiple of the suggested tip.
+type TipLevel struct {
+	Name       string
+	Multiplier float64
+}
+
+// ParseTipLevels parses a comma-separated list of tip multipliers such as "0,1x,2x,10x".
+func ParseTipLevels(spec string) ([]TipLevel, error) {
+	var levels []TipLevel
+	seen := make(map[string]bool)
+	for _, s := range SplitList(spec) {
+		m, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+		if err != nil || m < 0 {
+			return nil, fmt.Errorf("invalid tip level %q, must be a non-negative multiplier such as 2x", s)
+		}
+		name := strconv.FormatFloat(m, 'f', -1, 64) + "x"
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate tip level %q", s)
+		}
+		seen[name] = true
+		levels = append(levels, TipLevel{Name: name, Multiplier: m})
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("no tip levels given")
+	}
+	return levels, nil
+}
+
+// tip returns the level's priority fee for the suggested tip.
+func (l TipLevel) tip(suggested *big.Int) *big.Int {
+	tip, _ := new(big.Float).Mul(new(big.Float).SetInt(suggested), big.NewFloat(l.Multiplier)).Int(nil)
+	return tip
+}
+
+// FeeResult is the outcome of one transaction sent at one tip level.
+type FeeResult struct {
+	Level       string
+	TxIndex     int
+	TxHash      string
+	GasTipCap   *big.Int
+	SendTime    int64 // milliseconds
+	ConfirmTime int64 // milliseconds
+	TotalTime   int64 // milliseconds, -1 if not confirmed before the timeout
+	// BlockDelay is the inclusion block number minus the latest block number when the send started.
+	BlockDelay uint64
+	Rejected   bool   // the endpoint refused the tx, so its nonce was reused
+	Err        string // why the tx was not included, if it was not
+}
+
+// Confirmed reports whether the transaction was included before the timeout.
+func (r FeeResult) Confirmed() bool {
+	return r.TotalTime >= 0
+}
+
+// RunFeeComparison sends txCount transactions, cycling through the tip levels in a shuffled order every
+// round so they see comparable chain conditions. A transaction not included within timeout is cancelled
+// so later nonces are not blocked behind it. The run stops early at the spend limit; on an error, the
+// results so far are returned with it.
+func RunFeeComparison(txCount int, levels []TipLevel, pollInterval, timeout time.Duration) ([]FeeResult, error) {
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("no tip levels given")
+	}
+	client, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	chainID, err := getChainID(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	// Every tx raises its fee cap by its tip, so the balance check assumes the highest tip for all of them.
+	suggestedTip, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas tip cap: %w", err)
+	}
+	maxTip := new(big.Int)
+	for _, level := range levels {
+		maxTip = bigMax(maxTip, level.tip(suggestedTip))
+	}
+
+	signer := signers[0]
+	nonce, err := preflight(ctx, client, chainID, signer, txCount, maxTip)
+	if err != nil {
+		return nil, err
+	}
+	guard := spend
+
+	order := make([]int, len(levels))
+	for i := range order {
+		order[i] = i
+	}
+
+	results := make([]FeeResult, 0, txCount)
+	for i := 0; i < txCount; i++ {
+		if i%len(order) == 0 {
+			rand.Shuffle(len(order), func(a, b int) { order[a], order[b] = order[b], order[a] })
+		}
+		level := levels[order[i%len(order)]]
+
+		signedTx, err := signTipTransfer(ctx, client, chainID, signer, nonce, level)
+		if err != nil {
+			return results, err
+		}
+		if err := guard.allow(signedTx); err != nil {
+			Logger().Warn("stopping run at spend limit", "tx", i+1, "reason", err)
+			break
+		}
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return results, fmt.Errorf("failed to get block number: %w", err)
+		}
+
+		result := FeeResult{
+			Level:     level.Name,
+			TxIndex:   i + 1,
+			TxHash:    signedTx.Hash().Hex(),
+			GasTipCap: signedTx.GasTipCap(),
+			TotalTime: -1,
+		}
+
+		sendStart := time.Now()
+		if err := client.SendTransaction(ctx, signedTx); err != nil {
+			// A rejected tx does not use its nonce, so the next tx reuses it.
+			result.SendTime = time.Since(sendStart).Milliseconds()
+			result.Rejected = true
+			result.Err = err.Error()
+			Logger().Warn("tx rejected", "tx", i+1, "tip_level", level.Name, "err", err)
+			results = append(results, result)
+			continue
+		}
+		result.SendTime = time.Since(sendStart).Milliseconds()
+
+		confirmStart := time.Now()
+		confirmCtx, cancel := context.WithTimeout(ctx, timeout)
+		receipt, _, err := pollReceipt(confirmCtx, client, i+1, signedTx.Hash(), pollInterval)
+		cancel()
+		if err != nil {
+			result.Err = fmt.Sprintf("not included within %s", timeout)
+			Logger().Warn("tx not included, cancelling it", "tx", i+1, "tip_level", level.Name, "timeout", timeout)
+			included, receipt, err := cancelTransaction(ctx, client, i+1, signedTx, signer, chainID, pollInterval, timeout)
+			results = append(results, result)
+			if err != nil {
+				return results, err
+			}
+			guard.record(included, feeCost(ctx, client, i+1, receipt))
+			nonce++
+			continue
+		}
+		guard.record(signedTx, feeCost(ctx, client, i+1, receipt))
+
+		result.ConfirmTime = time.Since(confirmStart).Milliseconds()
+		result.TotalTime = time.Since(sendStart).Milliseconds()
+		if n := receipt.BlockNumber.Uint64(); n > head {
+			result.BlockDelay = n - head
+		}
+		Logger().Info("tx confirmed", "tx", i+1, "tip_level", level.Name, "tip_wei", result.GasTipCap,
+			"total_ms", result.TotalTime, "block_delay", result.BlockDelay)
+
+		results = append(results, result)
+		nonce++
+	}
+
+	return results, nil
+}
+
+// signTipTransfer signs a self-transfer at nonce paying level's multiple of the suggested tip. The fee
+// cap is raised by the tip so that the tip is always payable in full.
+func signTipTransfer(ctx context.Context, client *ethclient.Client, chainID *big.Int, signer Signer, nonce uint64, level TipLevel) (*types.Transaction, error) {
+	suggestedTip, gasFeeCap, err := suggestFees(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	gasTipCap := level.tip(suggestedTip)
+
+	toAddress := signer.Address()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap.Add(gasFeeCap, gasTipCap),
+		Gas:       selfTransferGas,
+		To:        &toAddress,
+		Value:     selfTransferValue,
+	})
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// cancelTransaction replaces tx with a zero-value self-transfer and waits until one of them is included.
+// It returns the included transaction and its receipt.
+func cancelTransaction(ctx context.Context, client *ethclient.Client, txIndex int, tx *types.Transaction, signer Signer,
+	chainID *big.Int, pollInterval, timeout time.Duration) (*types.Transaction, *types.Receipt, error) {
+	cancelTx, err := stuckReplacement(ctx, client, signer, chainID, tx, StuckCancel)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := client.SendTransaction(ctx, cancelTx); err != nil && !isAlreadyKnown(err) {
+		Logger().Warn("failed to send cancellation", "tx", txIndex, "err", err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	receipt, _, err := pollAnyReceipt(waitCtx, client, txIndex, []*types.Transaction{tx, cancelTx}, pollInterval)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tx %d and its cancellation were not included within %s", txIndex, timeout)
+	}
+	if receipt.TxHash == tx.Hash() {
+		return tx, receipt, nil
+	}
+	return cancelTx, receipt, nil
+}
+
+// feeCost returns the fee paid according to receipt, or nil so the spend guard counts the worst case.
+func feeCost(ctx context.Context, client *ethclient.Client, txIndex int, receipt *types.Receipt) *TxCost {
+	cost, err := fetchTxCost(ctx, client.Client(), receipt.TxHash)
+	if err != nil {
+		Logger().Warn("failed to get tx cost", "tx", txIndex, "err", err)
+		return nil
+	}
+	return cost
+}
+
+// FeeSummary aggregates the results of one tip level.
+type FeeSummary struct {
+	Level       string
+	Txs         int
+	Rejected    int      // refused by the endpoint
+	Unconfirmed int      // accepted but not included within the timeout
+	MedianTip   *big.Int // wei
+	Send        []int64  // sorted send times of the confirmed txs
+	Confirm     []int64  // sorted confirm times of the confirmed txs
+	Total       []int64  // sorted total times of the confirmed txs
+	BlockDelays []int64  // sorted block delays of the confirmed txs
+}
+
+// SummarizeFees aggregates results per tip level, in the order of levels.
+func SummarizeFees(results []FeeResult, levels []TipLevel) []FeeSummary {
+	summaries := make([]FeeSummary, 0, len(levels))
+	for _, level := range levels {
+		s := FeeSummary{Level: level.Name}
+		var tips []*big.Int
+		for _, r := range results {
+			if r.Level != level.Name {
+				continue
+			}
+			s.Txs++
+			tips = append(tips, r.GasTipCap)
+			if r.Rejected {
+				s.Rejected++
+				continue
+			}
+			if !r.Confirmed() {
+				s.Unconfirmed++
+				continue
+			}
+			s.Send = append(s.Send, r.SendTime)
+			s.Confirm = append(s.Confirm, r.ConfirmTime)
+			s.Total = append(s.Total, r.TotalTime)
+			s.BlockDelays = append(s.BlockDelays, int64(r.BlockDelay))
+		}
+		for _, values := range [][]int64{s.Send, s.Confirm, s.Total, s.BlockDelays} {
+			sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		}
+		if len(tips) > 0 {
+			sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+			s.MedianTip = tips[len(tips)/2]
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries
+}
+
+// PrintFeeReport prints the latency distribution of each tip level.
+func PrintFeeReport(summaries []FeeSummary) {
+	fmt.Println("\nPRIORITY FEE COMPARISON (total time, ms):")
+	fmt.Printf("%-8s %-14s %-6s %-9s %-8s %-8s %-8s %-8s %-8s %-8s %-10s\n",
+		"LEVEL", "TIP (gwei)", "TXS", "REJECTED", "UNCONF", "MIN", "P50", "P90", "P99", "MAX", "MED BLOCKS")
+	fmt.Println("---------------------------------------------------------------------------------------------------------")
+	for _, s := range summaries {
+		tip := "-"
+		if s.MedianTip != nil {
+			tip = formatGwei(s.MedianTip)
+		}
+		if len(s.Total) == 0 {
+			fmt.Printf("%-8s %-14s %-6d %-9d %-8d %-8s %-8s %-8s %-8s %-8s %-10s\n", s.Level, tip, s.Txs, s.Rejected, s.Unconfirmed,
+				"-", "-", "-", "-", "-", "-")
+			continue
+		}
+		fmt.Printf("%-8s %-14s %-6d %-9d %-8d %-8d %-8d %-8d %-8d %-8d %-10d\n", s.Level, tip, s.Txs, s.Rejected, s.Unconfirmed,
+			s.Total[0], percentile(s.Total, 50), percentile(s.Total, 90), percentile(s.Total, 99),
+			s.Total[len(s.Total)-1], percentile(s.BlockDelays, 50))
+	}
+}
+
+// formatGwei formats wei as a decimal gwei amount without trailing zeros.
+func formatGwei(wei *big.Int) string {
+	whole, frac := new(big.Int).QuoRem(wei, big.NewInt(params.GWei), new(big.Int))
+	if frac.Sign() == 0 {
+		return whole.String()
+	}
+	return whole.String() + "." + strings.TrimRight(fmt.Sprintf("%09d", new(big.Int).Abs(frac)), "0")
+}
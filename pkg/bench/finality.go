@@ -0,0 +1,168 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	finalityPollInterval time.Duration
+	finalityTimeout      time.Duration
+)
+
+// SetFinalityTracking makes subsequent async runs track every confirmed transaction until its block is
+// safe and finalized, checking the heads every pollInterval. Tracking runs alongside later submissions;
+// after the last one the run waits up to timeout for outstanding transactions. A zero timeout disables it.
+func SetFinalityTracking(pollInterval, timeout time.Duration) {
+	finalityPollInterval = pollInterval
+	finalityTimeout = timeout
+}
+
+// finalityTx is a confirmed transaction waiting for the safe and finalized heads to reach its block.
+type finalityTx struct {
+	resultIdx int
+	txIndex   int
+	block     uint64
+	sentAt    time.Time
+	safe      bool
+}
+
+// finalityTracker polls the safe and finalized heads in the background for the transactions added to it.
+type finalityTracker struct {
+	client       *rpc.Client
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	pending []*finalityTx
+	levels  map[int]map[ConfirmationLevel]int64 // by result index
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startFinalityTracker(ctx context.Context, client *rpc.Client, pollInterval time.Duration) *finalityTracker {
+	ctx, cancel := context.WithCancel(ctx)
+	t := &finalityTracker{
+		client:       client,
+		pollInterval: pollInterval,
+		levels:       make(map[int]map[ConfirmationLevel]int64),
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+	go t.run(ctx)
+	return t
+}
+
+// add starts tracking the transaction of results[resultIdx], included in block and sent at sentAt.
+func (t *finalityTracker) add(resultIdx, txIndex int, block uint64, sentAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, &finalityTx{resultIdx: resultIdx, txIndex: txIndex, block: block, sentAt: sentAt})
+}
+
+func (t *finalityTracker) outstanding() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+func (t *finalityTracker) run(ctx context.Context) {
+	defer close(t.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(t.pollInterval):
+		}
+		if t.outstanding() == 0 {
+			continue
+		}
+
+		safe, err := taggedBlockNumber(ctx, t.client, "safe")
+		if err != nil {
+			Logger().Warn("failed to get safe head", "err", err)
+			continue
+		}
+		finalized, err := taggedBlockNumber(ctx, t.client, "finalized")
+		if err != nil {
+			Logger().Warn("failed to get finalized head", "err", err)
+			continue
+		}
+		now := time.Now()
+
+		t.mu.Lock()
+		remaining := t.pending[:0]
+		for _, tx := range t.pending {
+			if !tx.safe && tx.block <= safe {
+				tx.safe = true
+				t.record(tx, LevelSafe, now)
+			}
+			if tx.block <= finalized {
+				if !tx.safe {
+					tx.safe = true
+					t.record(tx, LevelSafe, now)
+				}
+				t.record(tx, LevelFinalized, now)
+				continue
+			}
+			remaining = append(remaining, tx)
+		}
+		t.pending = remaining
+		t.mu.Unlock()
+	}
+}
+
+// record must be called with t.mu held.
+func (t *finalityTracker) record(tx *finalityTx, level ConfirmationLevel, at time.Time) {
+	if t.levels[tx.resultIdx] == nil {
+		t.levels[tx.resultIdx] = make(map[ConfirmationLevel]int64)
+	}
+	ms := at.Sub(tx.sentAt).Milliseconds()
+	t.levels[tx.resultIdx][level] = ms
+	Logger().Debug("confirmation level reached", "tx", tx.txIndex, "level", level, "ms", ms)
+}
+
+// wait blocks until every added transaction is finalized or timeout elapses, stops the tracker and
+// returns the levels reached by result index.
+func (t *finalityTracker) wait(timeout time.Duration) map[int]map[ConfirmationLevel]int64 {
+	if n := t.outstanding(); n > 0 {
+		Logger().Info("waiting for finality", "txs", n, "timeout", timeout)
+	}
+	deadline := time.Now().Add(timeout)
+	for t.outstanding() > 0 && time.Now().Before(deadline) {
+		time.Sleep(t.pollInterval)
+	}
+	if n := t.outstanding(); n > 0 {
+		Logger().Warn("transactions not finalized before timeout", "txs", n, "timeout", timeout)
+	}
+	t.stop()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.levels
+}
+
+// stop ends tracking without waiting for outstanding transactions.
+func (t *finalityTracker) stop() {
+	t.cancel()
+	<-t.done
+}
+
+// taggedBlockNumber returns the number of the block with the given tag, e.g. "safe" or "finalized".
+func taggedBlockNumber(ctx context.Context, client *rpc.Client, tag string) (uint64, error) {
+	var header struct {
+		Number *hexutil.Uint64 `json:"number"`
+	}
+	if err := client.CallContext(ctx, &header, "eth_getBlockByNumber", tag, false); err != nil {
+		return 0, err
+	}
+	if header.Number == nil {
+		return 0, fmt.Errorf("no %s block", tag)
+	}
+	return uint64(*header.Number), nil
+}
@@ -0,0 +1,184 @@
+package bench
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// assertionPattern matches "[metric.]stat op threshold", e.g. "p99 > +15%" or "confirm.median >= 1200ms".
+var assertionPattern = regexp.MustCompile(`^\s*(?:(send|confirm|total)\.)?(p\d+(?:\.\d+)?|min|max|avg|median)\s*(>=|<=|>|<)\s*([+-]?\d+(?:\.\d+)?)\s*(%|ms)?\s*$`)
+
+// ThresholdKind is how an assertion threshold is compared.
+type ThresholdKind int
+
+const (
+	// ThresholdAbsolute compares the statistic itself, in milliseconds.
+	ThresholdAbsolute ThresholdKind = iota
+	// ThresholdDelta compares the change from the baseline, in milliseconds.
+	ThresholdDelta
+	// ThresholdRelative compares the change from the baseline, in percent.
+	ThresholdRelative
+)
+
+// Assertion is a regression gate condition that fails the run when it holds, such as "p99 > +15%".
+type Assertion struct {
+	Raw       string
+	Metric    Metric
+	Stat      string
+	Op        string
+	Threshold float64
+	Kind      ThresholdKind
+}
+
+// ParseAssertion parses a fail condition "[metric.]stat op threshold". The metric is send, confirm or
+// total (default); the stat is pN, min, max, avg or median. A signed threshold ("+15%", "+200ms") is
+// compared with the change from the baseline, an unsigned one in ms ("1500ms") with the statistic itself.
+func ParseAssertion(s string) (Assertion, error) {
+	m := assertionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Assertion{}, fmt.Errorf("invalid assertion %q, expected e.g. \"p99 > +15%%\" or \"confirm.median > 1500ms\"", s)
+	}
+	a := Assertion{Raw: strings.TrimSpace(s), Metric: MetricTotal, Stat: m[2], Op: m[3]}
+	if m[1] != "" {
+		a.Metric = Metric(m[1])
+	}
+	if p, ok := strings.CutPrefix(a.Stat, "p"); ok {
+		if v, _ := strconv.ParseFloat(p, 64); v <= 0 || v > 100 {
+			return Assertion{}, fmt.Errorf("invalid assertion %q: percentile must be in (0, 100]", s)
+		}
+	}
+	a.Threshold, _ = strconv.ParseFloat(m[4], 64)
+
+	signed := strings.HasPrefix(m[4], "+") || strings.HasPrefix(m[4], "-")
+	switch {
+	case m[5] == "%":
+		a.Kind = ThresholdRelative
+	case signed:
+		a.Kind = ThresholdDelta
+	case m[5] == "ms":
+		a.Kind = ThresholdAbsolute
+	default:
+		return Assertion{}, fmt.Errorf("invalid assertion %q: threshold needs a unit, %% or ms", s)
+	}
+	return a, nil
+}
+
+// NeedsBaseline reports whether the assertion compares against a baseline run.
+func (a Assertion) NeedsBaseline() bool {
+	return a.Kind != ThresholdAbsolute
+}
+
+// AssertionResult is an assertion evaluated against a run.
+type AssertionResult struct {
+	Assertion Assertion
+	Current   float64 // statistic of the run, ms
+	Baseline  float64 // statistic of the baseline, ms; 0 without a baseline
+	Observed  float64 // the value compared with the threshold
+	Failed    bool
+}
+
+// EvaluateAssertions evaluates assertions on results, comparing with baseline where they need it.
+func EvaluateAssertions(assertions []Assertion, results, baseline []Result) ([]AssertionResult, error) {
+	if len(assertions) == 0 {
+		return nil, nil
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results to evaluate")
+	}
+	out := make([]AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		r := AssertionResult{Assertion: a, Current: metricStat(MetricValues(results, a.Metric), a.Stat)}
+		r.Observed = r.Current
+		if a.NeedsBaseline() {
+			if len(baseline) == 0 {
+				return nil, fmt.Errorf("assertion %q compares with a baseline, but none was loaded", a.Raw)
+			}
+			r.Baseline = metricStat(MetricValues(baseline, a.Metric), a.Stat)
+			r.Observed = r.Current - r.Baseline
+			if a.Kind == ThresholdRelative {
+				if r.Baseline == 0 {
+					return nil, fmt.Errorf("assertion %q: baseline %s %s is 0, use an ms threshold", a.Raw, a.Metric, a.Stat)
+				}
+				r.Observed = 100 * (r.Current - r.Baseline) / r.Baseline
+			}
+		}
+		switch a.Op {
+		case ">":
+			r.Failed = r.Observed > a.Threshold
+		case ">=":
+			r.Failed = r.Observed >= a.Threshold
+		case "<":
+			r.Failed = r.Observed < a.Threshold
+		case "<=":
+			r.Failed = r.Observed <= a.Threshold
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// metricStat returns stat of the ascending sorted values.
+func metricStat(sorted []int64, stat string) float64 {
+	switch stat {
+	case "min":
+		return float64(sorted[0])
+	case "max":
+		return float64(sorted[len(sorted)-1])
+	case "median":
+		return medianFloat(sorted)
+	case "avg":
+		var sum int64
+		for _, v := range sorted {
+			sum += v
+		}
+		return float64(sum) / float64(len(sorted))
+	default:
+		p, _ := strconv.ParseFloat(strings.TrimPrefix(stat, "p"), 64)
+		return float64(percentile(sorted, p))
+	}
+}
+
+// GateFailures returns the number of failed assertions.
+func GateFailures(results []AssertionResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Failed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// PrintGateReport prints every assertion with the values it was evaluated on.
+func PrintGateReport(results []AssertionResult) {
+	if len(results) == 0 {
+		return
+	}
+	fmt.Println("\nREGRESSION GATE:")
+	fmt.Printf("%-30s %-12s %-12s %-12s %s\n", "FAIL IF", "CURRENT", "BASELINE", "OBSERVED", "RESULT")
+	fmt.Println("--------------------------------------------------------------------------------")
+	for _, r := range results {
+		baseline, observed := "-", fmt.Sprintf("%.1fms", r.Observed)
+		if r.Assertion.NeedsBaseline() {
+			baseline = fmt.Sprintf("%.1fms", r.Baseline)
+		}
+		switch r.Assertion.Kind {
+		case ThresholdDelta:
+			observed = fmt.Sprintf("%+.1fms", r.Observed)
+		case ThresholdRelative:
+			observed = fmt.Sprintf("%+.1f%%", r.Observed)
+		}
+		result := "PASS"
+		if r.Failed {
+			result = "FAIL"
+		}
+		fmt.Printf("%-30s %-12s %-12s %-12s %s\n", r.Assertion.Raw, fmt.Sprintf("%.1fms", r.Current), baseline, observed, result)
+	}
+	if failed := GateFailures(results); failed > 0 {
+		fmt.Printf("\n%d of %d assertions failed\n", failed, len(results))
+	} else {
+		fmt.Printf("\nAll %d assertions passed\n", len(results))
+	}
+}
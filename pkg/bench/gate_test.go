@@ -0,0 +1,88 @@
+package bench
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseAssertion(t *testing.T) {
+	tests := []struct {
+		in        string
+		metric    Metric
+		stat      string
+		op        string
+		threshold float64
+		kind      ThresholdKind
+		err       bool
+	}{
+		{in: "p99 > +15%", metric: MetricTotal, stat: "p99", op: ">", threshold: 15, kind: ThresholdRelative},
+		{in: "confirm.median > +200ms", metric: MetricConfirm, stat: "median", op: ">", threshold: 200, kind: ThresholdDelta},
+		{in: "max > 1500ms", metric: MetricTotal, stat: "max", op: ">", threshold: 1500, kind: ThresholdAbsolute},
+		{in: " send.p99.9 <= -5% ", metric: MetricSend, stat: "p99.9", op: "<=", threshold: -5, kind: ThresholdRelative},
+		{in: "avg >= +0ms", metric: MetricTotal, stat: "avg", op: ">=", threshold: 0, kind: ThresholdDelta},
+		{in: "p99 > 1500", err: true},
+		{in: "p0 > 1500ms", err: true},
+		{in: "p101 > 1500ms", err: true},
+		{in: "sign.p99 > 1500ms", err: true},
+		{in: "p99 == 1500ms", err: true},
+	}
+	for _, tt := range tests {
+		a, err := ParseAssertion(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if a.Metric != tt.metric || a.Stat != tt.stat || a.Op != tt.op || a.Threshold != tt.threshold || a.Kind != tt.kind {
+			t.Errorf("%q: got %+v", tt.in, a)
+		}
+	}
+}
+
+func TestEvaluateAssertions(t *testing.T) {
+	// Total times 100..1000 ms against a baseline of 80..800 ms: p99 is 1000 vs 800, +200ms or +25%.
+	var results, baseline []Result
+	for i := 1; i <= 10; i++ {
+		results = append(results, Result{TxIndex: i, ConfirmTime: int64(100*i - 10), TotalTime: int64(100 * i)})
+		baseline = append(baseline, Result{TxIndex: i, ConfirmTime: int64(80*i - 10), TotalTime: int64(80 * i)})
+	}
+
+	tests := []struct {
+		in       string
+		observed float64
+		failed   bool
+	}{
+		{"p99 > +15%", 25, true},
+		{"p99 > +30%", 25, false},
+		{"p99 > +200ms", 200, false},
+		{"p99 >= +200ms", 200, true},
+		{"max > 1500ms", 1000, false},
+		{"max > 900ms", 1000, true},
+		{"confirm.median > 500ms", 540, true},
+		{"min < -10%", 25, false},
+	}
+	for _, tt := range tests {
+		a, err := ParseAssertion(tt.in)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.in, err)
+		}
+		evaluated, err := EvaluateAssertions([]Assertion{a}, results, baseline)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.in, err)
+		}
+		r := evaluated[0]
+		if math.Abs(r.Observed-tt.observed) > 1e-9 || r.Failed != tt.failed {
+			t.Errorf("%q: got observed %g failed %v, want %g %v", tt.in, r.Observed, r.Failed, tt.observed, tt.failed)
+		}
+	}
+
+	a, _ := ParseAssertion("p99 > +15%")
+	if _, err := EvaluateAssertions([]Assertion{a}, results, nil); err == nil {
+		t.Error("relative assertion without a baseline: expected an error")
+	}
+}
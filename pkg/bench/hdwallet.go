@@ -0,0 +1,127 @@
+package bench
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// bip39English is the BIP-39 English wordlist, one word per line in index order.
+//
+//go:embed bip39_english.txt
+var bip39English string
+
+// bip39Index maps each BIP-39 English word to its 11-bit index.
+var bip39Index = func() map[string]int {
+	index := make(map[string]int, 2048)
+	for i, word := range strings.Fields(bip39English) {
+		index[word] = i
+	}
+	return index
+}()
+
+// deriveMnemonicKeys derives count keys from a BIP-39 mnemonic at basePath/0 .. basePath/count-1 (BIP-32).
+func deriveMnemonicKeys(mnemonic, passphrase, basePath string, count int) ([]*ecdsa.PrivateKey, error) {
+	words := strings.Fields(mnemonic)
+	if err := checkMnemonic(words); err != nil {
+		return nil, err
+	}
+	base, err := accounts.ParseDerivationPath(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HD path %q: %w", basePath, err)
+	}
+
+	seed := pbkdf2.Key([]byte(norm.NFKD.String(strings.Join(words, " "))),
+		[]byte(norm.NFKD.String("mnemonic"+passphrase)), 2048, 64, sha512.New)
+
+	keys := make([]*ecdsa.PrivateKey, 0, count)
+	for i := 0; i < count; i++ {
+		path := append(append(accounts.DerivationPath(nil), base...), uint32(i))
+		key, err := deriveHDKey(seed, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// checkMnemonic verifies that words are from the English wordlist and end with a valid BIP-39 checksum.
+func checkMnemonic(words []string) error {
+	n := len(words)
+	if n < 12 || n > 24 || n%3 != 0 {
+		return fmt.Errorf("invalid mnemonic: expected 12 to 24 words, got %d", n)
+	}
+
+	// The words encode 11 bits each: the entropy followed by the first len(entropy)/32 bits of its SHA-256.
+	bits := new(big.Int)
+	for i, word := range words {
+		index, ok := bip39Index[word]
+		if !ok {
+			return fmt.Errorf("invalid mnemonic: word %d %q is not in the BIP-39 English wordlist", i+1, word)
+		}
+		bits.Lsh(bits, 11).Or(bits, big.NewInt(int64(index)))
+	}
+	checksumBits := uint(n / 3)
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1))
+	entropy := new(big.Int).Rsh(bits, checksumBits).FillBytes(make([]byte, n*4/3))
+
+	sum := sha256.Sum256(entropy)
+	if want := uint64(sum[0] >> (8 - checksumBits)); checksum.Uint64() != want {
+		return fmt.Errorf("invalid mnemonic: checksum mismatch")
+	}
+	return nil
+}
+
+// deriveHDKey derives the private key at path from a BIP-32 seed.
+func deriveHDKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	n := crypto.S256().Params().N
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	k, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	if k.Sign() == 0 || k.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("invalid master key")
+	}
+
+	for _, index := range path {
+		parent, err := crypto.ToECDSA(k.FillBytes(make([]byte, 32)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent key at index %d: %w", index, err)
+		}
+		var data []byte
+		if index >= 0x80000000 {
+			data = append([]byte{0}, crypto.FromECDSA(parent)...)
+		} else {
+			data = crypto.CompressPubkey(&parent.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		k = il.Add(il, k).Mod(il, n)
+		if k.Sign() == 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(k.FillBytes(make([]byte, 32)))
+}
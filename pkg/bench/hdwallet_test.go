@@ -0,0 +1,66 @@
+package bench
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestDeriveHDKey checks the private keys of BIP-32 test vector 1.
+func TestDeriveHDKey(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, tt := range tests {
+		path, err := accounts.ParseDerivationPath(tt.path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		key, err := deriveHDKey(seed, path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if got := hex.EncodeToString(crypto.FromECDSA(key)); got != tt.key {
+			t.Errorf("%s: got key %s, want %s", tt.path, got, tt.key)
+		}
+	}
+}
+
+func TestDeriveMnemonicKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		address  string // of the first key, empty when the mnemonic is invalid
+	}{
+		{"valid", "test test test test test test test test test test test junk", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+		{"bad checksum", "test test test test test test test test test test test test", ""},
+		{"unknown word", "test test test test test test test test test test test jnuk", ""},
+		{"wrong length", "test test test test test test test test test test junk", ""},
+	}
+	for _, tt := range tests {
+		keys, err := deriveMnemonicKeys(tt.mnemonic, "", "m/44'/60'/0'/0", 1)
+		if tt.address == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := crypto.PubkeyToAddress(keys[0].PublicKey).Hex(); !strings.EqualFold(got, tt.address) {
+			t.Errorf("%s: got address %s, want %s", tt.name, got, tt.address)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ConfirmationLevel is a milestone a transaction passes on its way to finality.
+type ConfirmationLevel string
+
+const (
+	// LevelMempool is reached when the endpoint accepts the transaction.
+	LevelMempool ConfirmationLevel = "mempool"
+	// LevelPending is reached when eth_getTransactionByHash returns the transaction.
+	LevelPending ConfirmationLevel = "pending"
+	// LevelPreconfirmed is reached when the transaction is in the pending block (flashblocks, shreds), or
+	// when a realtime API returns its receipt before the canonical block.
+	LevelPreconfirmed ConfirmationLevel = "preconfirmed"
+	// LevelIncluded is reached when the receipt of the transaction is available.
+	LevelIncluded ConfirmationLevel = "included"
+	// LevelSafe is reached when the inclusion block is at or below the safe head.
+	LevelSafe ConfirmationLevel = "safe"
+	// LevelFinalized is reached when the inclusion block is at or below the finalized head.
+	LevelFinalized ConfirmationLevel = "finalized"
+)
+
+// ConfirmationLevels lists every level in the order a transaction reaches them.
+var ConfirmationLevels = []ConfirmationLevel{
+	LevelMempool, LevelPending, LevelPreconfirmed, LevelIncluded, LevelSafe, LevelFinalized,
+}
+
+var trackLevels bool
+
+// SetLevelTracking enables polling for the pending and preconfirmed levels while waiting for receipts in
+// subsequent async runs. It adds RPC load alongside receipt polling, so it is off by default.
+func SetLevelTracking(enabled bool) {
+	trackLevels = enabled
+}
+
+// levelRecorder records the first time each level is reached, relative to start.
+type levelRecorder struct {
+	mu     sync.Mutex
+	start  time.Time
+	txIdx  int
+	levels map[ConfirmationLevel]int64
+}
+
+func newLevelRecorder(txIndex int, start time.Time) *levelRecorder {
+	return &levelRecorder{start: start, txIdx: txIndex, levels: make(map[ConfirmationLevel]int64)}
+}
+
+// observe records level as reached at t, unless it was reached earlier.
+func (r *levelRecorder) observe(level ConfirmationLevel, t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.levels[level]; ok {
+		return
+	}
+	r.levels[level] = t.Sub(r.start).Milliseconds()
+	Logger().Debug("confirmation level reached", "tx", r.txIdx, "level", level, "ms", r.levels[level])
+}
+
+func (r *levelRecorder) seen(level ConfirmationLevel) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.levels[level]
+	return ok
+}
+
+// snapshot returns a copy of the levels reached so far.
+func (r *levelRecorder) snapshot() map[ConfirmationLevel]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[ConfirmationLevel]int64, len(r.levels))
+	for k, v := range r.levels {
+		out[k] = v
+	}
+	return out
+}
+
+// trackPreconfirmation polls for txHash in the node's transaction pool and in the pending block every
+// pollInterval until both are seen or ctx is done.
+func trackPreconfirmation(ctx context.Context, client *rpc.Client, txHash common.Hash, pollInterval time.Duration, rec *levelRecorder) {
+	for !rec.seen(LevelPending) || !rec.seen(LevelPreconfirmed) {
+		if !rec.seen(LevelPending) {
+			var tx json.RawMessage
+			err := client.CallContext(ctx, &tx, "eth_getTransactionByHash", txHash)
+			if err == nil && len(tx) > 0 && string(tx) != "null" {
+				rec.observe(LevelPending, time.Now())
+			}
+		}
+		if !rec.seen(LevelPreconfirmed) {
+			var block struct {
+				Transactions []common.Hash `json:"transactions"`
+			}
+			if err := client.CallContext(ctx, &block, "eth_getBlockByNumber", "pending", false); err == nil {
+				for _, h := range block.Transactions {
+					if h == txHash {
+						rec.observe(LevelPreconfirmed, time.Now())
+						break
+					}
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// PrintLevelsReport prints the latency from send to each confirmation level that was observed.
+func PrintLevelsReport(results []Result) {
+	if len(results) == 0 {
+		return
+	}
+	fmt.Println("\nCONFIRMATION LEVELS (ms from send):")
+	fmt.Printf("%-14s %-10s %-10s %-10s %-10s %-10s %-10s\n", "LEVEL", "SEEN", "MIN", "MEDIAN", "P90", "P99", "MAX")
+	fmt.Println("------------------------------------------------------------------------------")
+	for _, level := range ConfirmationLevels {
+		values := levelValues(results, level)
+		if len(values) == 0 {
+			fmt.Printf("%-14s %-10s\n", level, fmt.Sprintf("0/%d", len(results)))
+			continue
+		}
+		fmt.Printf("%-14s %-10s %-10d %-10d %-10d %-10d %-10d\n", level, fmt.Sprintf("%d/%d", len(values), len(results)),
+			values[0], median(values), percentile(values, 90), percentile(values, 99), values[len(values)-1])
+	}
+}
+
+// levelValues returns the ascending latencies to level of the results that reached it.
+func levelValues(results []Result, level ConfirmationLevel) []int64 {
+	var values []int64
+	for _, r := range results {
+		if v, ok := r.Levels[level]; ok {
+			values = append(values, v)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
@@ -0,0 +1,229 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcLimitExceededCode is the JSON-RPC error code providers return when a request exceeds a limit (EIP-1474).
+const rpcLimitExceededCode = -32005
+
+// LoadStep is the outcome of running a call at one target rate.
+type LoadStep struct {
+	TargetQPS   float64
+	Duration    time.Duration
+	Sent        int
+	Skipped     int // ticks dropped because every worker was busy
+	Errors      int // failures other than rate limiting
+	RateLimited int
+	LastError   string
+	Latencies   []time.Duration // successful calls, ascending
+}
+
+// AchievedQPS returns the rate of successful calls over the step.
+func (s LoadStep) AchievedQPS() float64 {
+	return float64(len(s.Latencies)) / s.Duration.Seconds()
+}
+
+// ErrorRate returns the share of sent calls that failed, including rate-limited ones.
+func (s LoadStep) ErrorRate() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Errors+s.RateLimited) / float64(s.Sent)
+}
+
+// LoadConfig configures a ramped load test.
+type LoadConfig struct {
+	Workers      int
+	StartQPS     float64
+	StepQPS      float64
+	MaxQPS       float64
+	StepDuration time.Duration
+	// MaxErrorRate is the share of failed calls above which a step is considered unsustainable.
+	MaxErrorRate float64
+	// MaxLatencyInflation is the factor by which p90 may exceed the first step's p90 before a step is
+	// considered unsustainable.
+	MaxLatencyInflation float64
+}
+
+// LoadResult is the outcome of a ramped load test.
+type LoadResult struct {
+	Method string
+	Steps  []LoadStep
+	// Sustainable is the index of the last step that stayed within the error and latency limits, -1 if none did.
+	Sustainable int
+	// StopReason explains why the ramp stopped before MaxQPS, if it did.
+	StopReason string
+}
+
+// RunLoadTest calls call at an increasing target rate, from StartQPS by StepQPS up to MaxQPS, and stops
+// at the first step that exceeds the error or latency limits.
+func RunLoadTest(ctx context.Context, client *rpc.Client, call RPCCall, cfg LoadConfig) LoadResult {
+	name := call.Name
+	if name == "" {
+		name = call.Method
+	}
+	result := LoadResult{Method: name, Sustainable: -1}
+
+	var baselineP90 time.Duration
+	for qps := cfg.StartQPS; qps <= cfg.MaxQPS; qps += cfg.StepQPS {
+		Logger().Info("running load step", "method", call.Method, "target_qps", qps, "duration", cfg.StepDuration)
+		step := runLoadStep(ctx, client, call, cfg.Workers, qps, cfg.StepDuration)
+		result.Steps = append(result.Steps, step)
+
+		Logger().Info("load step finished", "target_qps", qps, "achieved_qps", step.AchievedQPS(),
+			"errors", step.Errors, "rate_limited", step.RateLimited, "skipped", step.Skipped)
+
+		if len(step.Latencies) == 0 {
+			result.StopReason = fmt.Sprintf("all calls failed at %.0f QPS: %s", qps, step.LastError)
+			break
+		}
+		if step.RateLimited > 0 && step.ErrorRate() > cfg.MaxErrorRate {
+			result.StopReason = fmt.Sprintf("rate limited at %.0f QPS (%d calls): %s", qps, step.RateLimited, step.LastError)
+			break
+		}
+		if step.ErrorRate() > cfg.MaxErrorRate {
+			result.StopReason = fmt.Sprintf("error rate %.1f%% at %.0f QPS: %s", step.ErrorRate()*100, qps, step.LastError)
+			break
+		}
+
+		p90 := percentile(step.Latencies, 90)
+		if baselineP90 == 0 {
+			baselineP90 = p90
+		} else if float64(p90) > float64(baselineP90)*cfg.MaxLatencyInflation {
+			result.StopReason = fmt.Sprintf("p90 latency inflated from %v to %v at %.0f QPS", baselineP90, p90, qps)
+			break
+		}
+		if step.Skipped > 0 && float64(step.Skipped)/float64(step.Sent+step.Skipped) > cfg.MaxErrorRate {
+			result.StopReason = fmt.Sprintf("%d workers cannot keep up with %.0f QPS; increase --workers", cfg.Workers, qps)
+			break
+		}
+
+		result.Sustainable = len(result.Steps) - 1
+		if cfg.StepQPS <= 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// runLoadStep issues call at qps for duration using up to workers concurrent requests.
+func runLoadStep(ctx context.Context, client *rpc.Client, call RPCCall, workers int, qps float64, duration time.Duration) LoadStep {
+	step := LoadStep{TargetQPS: qps, Duration: duration}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	ticks := make(chan struct{}, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range ticks {
+				var result json.RawMessage
+				start := time.Now()
+				err := client.CallContext(ctx, &result, call.Method, call.Params...)
+				elapsed := time.Since(start)
+
+				mu.Lock()
+				step.Sent++
+				switch {
+				case err == nil:
+					step.Latencies = append(step.Latencies, elapsed)
+				case isRateLimited(err):
+					step.RateLimited++
+					step.LastError = err.Error()
+				default:
+					step.Errors++
+					step.LastError = err.Error()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Dispatch whatever is due on each tick, so the rate holds even when ticks are late or coalesced.
+	interval := time.Duration(float64(time.Second) / qps)
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	deadline := time.After(duration)
+	start := time.Now()
+	dispatched := 0
+loop:
+	for {
+		select {
+		case now := <-ticker.C:
+			due := int(now.Sub(start).Seconds() * qps)
+			for ; dispatched < due; dispatched++ {
+				select {
+				case ticks <- struct{}{}:
+				default:
+					step.Skipped++
+				}
+			}
+		case <-deadline:
+			break loop
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	ticker.Stop()
+	close(ticks)
+	wg.Wait()
+
+	sort.Slice(step.Latencies, func(i, j int) bool { return step.Latencies[i] < step.Latencies[j] })
+	return step
+}
+
+// isRateLimited reports whether err indicates the provider is throttling requests:
+// HTTP 429, JSON-RPC limit exceeded (-32005) or a rate limit message.
+func isRateLimited(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == 429 {
+		return true
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcLimitExceededCode {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "rate limit") || strings.Contains(msg, "too many requests")
+}
+
+// PrintLoadReport prints each load step and the maximum sustainable rate.
+func PrintLoadReport(result LoadResult) {
+	fmt.Printf("\nLOAD TEST: %s\n", result.Method)
+	fmt.Printf("%-10s %-10s %-8s %-8s %-8s %-8s %-9s %-9s %-9s\n",
+		"TARGET", "ACHIEVED", "SENT", "ERRORS", "LIMITED", "SKIPPED", "P50 (ms)", "P90 (ms)", "P99 (ms)")
+	fmt.Println("-----------------------------------------------------------------------------------------")
+
+	for _, s := range result.Steps {
+		p50, p90, p99 := "-", "-", "-"
+		if len(s.Latencies) > 0 {
+			p50, p90, p99 = formatMs(percentile(s.Latencies, 50)), formatMs(percentile(s.Latencies, 90)), formatMs(percentile(s.Latencies, 99))
+		}
+		fmt.Printf("%-10.0f %-10.1f %-8d %-8d %-8d %-8d %-9s %-9s %-9s\n",
+			s.TargetQPS, s.AchievedQPS(), s.Sent, s.Errors, s.RateLimited, s.Skipped, p50, p90, p99)
+	}
+
+	if result.Sustainable >= 0 {
+		s := result.Steps[result.Sustainable]
+		fmt.Printf("\nMax sustainable QPS: %.1f (target %.0f)\n", s.AchievedQPS(), s.TargetQPS)
+	} else {
+		fmt.Println("\nNo sustainable rate found")
+	}
+	if result.StopReason != "" {
+		fmt.Printf("Stopped: %s\n", result.StopReason)
+	}
+}
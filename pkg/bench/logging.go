@@ -0,0 +1,129 @@
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// logBufferSize is the number of pending log records held before new records are dropped.
+const logBufferSize = 8192
+
+// sharedLogger is read by the runner goroutines while SetupLogger may replace it, so it is held atomically.
+var sharedLogger atomic.Pointer[slog.Logger]
+
+func init() {
+	sharedLogger.Store(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+}
+
+// Logger returns the logger shared by the runners, reports and commands.
+func Logger() *slog.Logger {
+	return sharedLogger.Load()
+}
+
+// SetupLogger replaces the shared logger with one writing records of at least the given level
+// ("debug", "info", "warn", "error") in the given format ("text" or "json") to w.
+// Writes happen on a background goroutine so logging never blocks the timed loops;
+// the returned function restores the previous logger and flushes pending records.
+func SetupLogger(w io.Writer, level, format string) (func() error, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	format = strings.ToLower(format)
+	if format != "text" && format != "json" {
+		return nil, fmt.Errorf("invalid log format: %s, must be 'text' or 'json'", format)
+	}
+
+	aw := newAsyncWriter(w)
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler = slog.NewTextHandler(aw, opts)
+	if format == "json" {
+		handler = slog.NewJSONHandler(aw, opts)
+	}
+
+	next := slog.New(handler)
+	prev := sharedLogger.Swap(next)
+	slog.SetDefault(next)
+
+	return func() error {
+		sharedLogger.Store(prev)
+		slog.SetDefault(prev)
+		return aw.Close()
+	}, nil
+}
+
+// asyncWriter hands writes off to a goroutine that owns the underlying buffered writer.
+// If the queue is full, records are dropped and counted rather than stalling the caller.
+type asyncWriter struct {
+	queue   chan []byte
+	done    chan struct{}
+	dropped atomic.Int64
+	mu      sync.RWMutex // guards closed against concurrent Write and Close
+	closed  bool
+	err     error
+}
+
+func newAsyncWriter(w io.Writer) *asyncWriter {
+	aw := &asyncWriter{
+		queue: make(chan []byte, logBufferSize),
+		done:  make(chan struct{}),
+	}
+	go func() {
+		defer close(aw.done)
+		bw := bufio.NewWriter(w)
+		for p := range aw.queue {
+			if _, err := bw.Write(p); err != nil && aw.err == nil {
+				aw.err = err
+			}
+			// Flush whenever the queue drains so logs stay reasonably live.
+			if len(aw.queue) == 0 {
+				_ = bw.Flush()
+			}
+		}
+		if n := aw.dropped.Load(); n > 0 {
+			fmt.Fprintf(bw, "%d log records dropped: log buffer full\n", n)
+		}
+		if err := bw.Flush(); err != nil && aw.err == nil {
+			aw.err = err
+		}
+	}()
+	return aw
+}
+
+func (aw *asyncWriter) Write(p []byte) (int, error) {
+	// Handlers reuse their buffers, so the record must be copied before queueing.
+	buf := make([]byte, len(p))
+	copy(buf, p)
+	aw.mu.RLock()
+	defer aw.mu.RUnlock()
+	if aw.closed {
+		// A goroutine may still hold the restored logger's handler; its records are discarded.
+		return len(p), nil
+	}
+	select {
+	case aw.queue <- buf:
+	default:
+		aw.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Close flushes all queued records. Writes after Close are discarded.
+func (aw *asyncWriter) Close() error {
+	aw.mu.Lock()
+	if !aw.closed {
+		aw.closed = true
+		close(aw.queue)
+	}
+	aw.mu.Unlock()
+	<-aw.done
+	return aw.err
+}
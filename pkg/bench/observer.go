@@ -0,0 +1,38 @@
+package bench
+
+// Observer receives live progress events from the benchmark runners.
+// Implementations must be safe for concurrent use.
+type Observer interface {
+	// TxSent is called once a transaction has been accepted by the RPC endpoint.
+	TxSent(txIndex int, txHash string)
+	// TxConfirmed is called once the receipt of a transaction has been observed.
+	TxConfirmed(r Result)
+	// TxFailed is called when a transaction could not be sent or confirmed.
+	TxFailed(txIndex int, err error)
+	// PollFailed is called when a receipt query fails for a reason other than the receipt not being available yet.
+	PollFailed(txIndex int, err error)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) TxSent(int, string)    {}
+func (nopObserver) TxConfirmed(Result)    {}
+func (nopObserver) TxFailed(int, error)   {}
+func (nopObserver) PollFailed(int, error) {}
+
+var observer Observer = nopObserver{}
+
+// SetObserver registers o to receive progress events from subsequent runs. Passing nil disables it.
+func SetObserver(o Observer) {
+	if o == nil {
+		o = nopObserver{}
+	}
+	observer = o
+}
+
+// observed reports whether an observer such as the dashboard is registered. It may be drawing on stdout,
+// so runners log what they would otherwise print.
+func observed() bool {
+	_, nop := observer.(nopObserver)
+	return !nop
+}
@@ -0,0 +1,122 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+)
+
+// OutlierMethod selects how outliers are detected.
+type OutlierMethod string
+
+const (
+	OutliersNone OutlierMethod = "none"
+	// OutliersIQR flags values more than k interquartile ranges below the first or above the third quartile.
+	OutliersIQR OutlierMethod = "iqr"
+	// OutliersMAD flags values whose modified z-score, based on the median absolute deviation, exceeds k.
+	OutliersMAD OutlierMethod = "mad"
+)
+
+// ParseOutlierMethod parses an outlier detection method name.
+func ParseOutlierMethod(s string) (OutlierMethod, error) {
+	switch m := OutlierMethod(s); m {
+	case OutliersNone, OutliersIQR, OutliersMAD:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown outlier method %q, must be 'none', 'iqr' or 'mad'", s)
+	}
+}
+
+// DefaultOutlierThreshold returns the conventional k of method: Tukey's 1.5 for IQR and Iglewicz and
+// Hoaglin's 3.5 for MAD.
+func DefaultOutlierThreshold(method OutlierMethod) float64 {
+	if method == OutliersMAD {
+		return 3.5
+	}
+	return 1.5
+}
+
+// OutlierReport is the outcome of outlier detection on one metric.
+type OutlierReport struct {
+	Method    OutlierMethod
+	Threshold float64
+	Metric    Metric
+	Low, High float64  // values outside [Low, High] are outliers, in ms
+	Outliers  []Result // in tx order
+	Inliers   []Result
+}
+
+// DetectOutliers splits results into inliers and outliers by metric. Nothing is removed from results.
+func DetectOutliers(results []Result, metric Metric, method OutlierMethod, k float64) OutlierReport {
+	report := OutlierReport{Method: method, Threshold: k, Metric: metric, Low: math.Inf(-1), High: math.Inf(1)}
+	values := MetricValues(results, metric)
+	if method == OutliersNone || len(values) < 4 {
+		report.Inliers = results
+		return report
+	}
+
+	switch method {
+	case OutliersIQR:
+		q1, q3 := quantile(values, 0.25), quantile(values, 0.75)
+		iqr := q3 - q1
+		report.Low, report.High = q1-k*iqr, q3+k*iqr
+	case OutliersMAD:
+		med := medianFloat(values)
+		deviations := make([]int64, len(values))
+		for i, v := range values {
+			deviations[i] = int64(math.Abs(float64(v) - med))
+		}
+		// 0.6745 scales the MAD to the standard deviation of a normal distribution.
+		if mad := medianFloat(deviations); mad > 0 {
+			report.Low, report.High = med-k*mad/0.6745, med+k*mad/0.6745
+		}
+	}
+
+	for _, r := range results {
+		v := float64(metric.value(r))
+		if v < report.Low || v > report.High {
+			report.Outliers = append(report.Outliers, r)
+		} else {
+			report.Inliers = append(report.Inliers, r)
+		}
+	}
+	return report
+}
+
+// quantile returns the q-quantile of the ascending sorted values with linear interpolation.
+func quantile(sorted []int64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := min(lo+1, len(sorted)-1)
+	return float64(sorted[lo]) + (pos-float64(lo))*float64(sorted[hi]-sorted[lo])
+}
+
+// PrintOutlierReport lists the outliers and compares the statistics with and without them.
+func PrintOutlierReport(report OutlierReport, all []Result) {
+	if report.Method == OutliersNone {
+		return
+	}
+	fmt.Printf("\nOUTLIERS (%s, k=%g on %s time): %d of %d txs outside [%.0f, %.0f] ms\n",
+		report.Method, report.Threshold, report.Metric, len(report.Outliers), len(all), report.Low, report.High)
+	if len(report.Outliers) == 0 {
+		return
+	}
+
+	for _, r := range report.Outliers {
+		fmt.Printf("  tx %-5d send %-6d confirm %-6d total %-6d %s\n", r.TxIndex, r.SendTime, r.ConfirmTime, r.TotalTime,
+			truncateHash(r.TxHash))
+	}
+
+	fmt.Printf("\n%-22s %-8s %-10s %-10s %-10s %-10s %-10s\n", report.Metric+" time (ms)", "TXS", "MIN", "MEDIAN", "AVG", "P99", "MAX")
+	fmt.Println("-----------------------------------------------------------------------------------")
+	for _, row := range []struct {
+		name    string
+		results []Result
+	}{{"All txs", all}, {"Without outliers", report.Inliers}} {
+		values := MetricValues(row.results, report.Metric)
+		if len(values) == 0 {
+			continue
+		}
+		fmt.Printf("%-22s %-8d %-10d %-10.0f %-10.1f %-10d %-10d\n", row.name, len(values), values[0],
+			medianFloat(values), metricStat(values, "avg"), percentile(values, 99), values[len(values)-1])
+	}
+}
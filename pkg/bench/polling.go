@@ -0,0 +1,345 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// blockTimeSampleBlocks is how many recent blocks are used to estimate the block time.
+const blockTimeSampleBlocks = 100
+
+// PollStrategy decides how long to wait between receipt polls.
+type PollStrategy interface {
+	Name() string
+	// Prepare is called right before the transaction to be polled for is sent.
+	Prepare(ctx context.Context, client *ethclient.Client) error
+	// Delay returns how long to wait after failedPolls unsuccessful polls.
+	Delay(failedPolls int) time.Duration
+}
+
+// FixedPoll polls at a constant interval.
+type FixedPoll struct {
+	Interval time.Duration
+}
+
+func (p FixedPoll) Name() string                                     { return "fixed:" + p.Interval.String() }
+func (p FixedPoll) Prepare(context.Context, *ethclient.Client) error { return nil }
+func (p FixedPoll) Delay(int) time.Duration                          { return p.Interval }
+
+// BackoffPoll starts at Initial and multiplies the interval by Factor after every miss, up to Max.
+type BackoffPoll struct {
+	Initial time.Duration
+	Factor  float64
+	Max     time.Duration
+}
+
+func (p BackoffPoll) Name() string {
+	return fmt.Sprintf("backoff:%v:%g:%v", p.Initial, p.Factor, p.Max)
+}
+func (p BackoffPoll) Prepare(context.Context, *ethclient.Client) error { return nil }
+func (p BackoffPoll) Delay(failedPolls int) time.Duration {
+	d := time.Duration(float64(p.Initial) * math.Pow(p.Factor, float64(failedPolls-1)))
+	if d > p.Max || d <= 0 {
+		return p.Max
+	}
+	return d
+}
+
+// JitterPoll polls at Interval randomised uniformly by ±Jitter (a fraction of Interval).
+type JitterPoll struct {
+	Interval time.Duration
+	Jitter   float64
+}
+
+func (p JitterPoll) Name() string                                     { return fmt.Sprintf("jitter:%v:%g", p.Interval, p.Jitter) }
+func (p JitterPoll) Prepare(context.Context, *ethclient.Client) error { return nil }
+func (p JitterPoll) Delay(int) time.Duration {
+	return time.Duration(float64(p.Interval) * (1 + p.Jitter*(2*rand.Float64()-1)))
+}
+
+// BlockTimePoll sleeps until the next block is expected, then polls at a fraction of the block time.
+// The block time is estimated from recent headers; the next block is expected one block time after
+// the latest header. Header timestamps have second resolution, so on sub-second chains the first
+// wait is at most one block time.
+type BlockTimePoll struct {
+	blockTime time.Duration
+	nextBlock time.Time
+}
+
+func (p *BlockTimePoll) Name() string { return "blocktime" }
+
+func (p *BlockTimePoll) Prepare(ctx context.Context, client *ethclient.Client) error {
+	latest, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %w", err)
+	}
+
+	if p.blockTime == 0 {
+		n := latest.Number.Uint64()
+		if n < 1 {
+			return fmt.Errorf("not enough blocks to estimate the block time")
+		}
+		span := min(uint64(blockTimeSampleBlocks), n)
+		old, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(n-span))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", n-span, err)
+		}
+		p.blockTime = time.Duration(float64(latest.Time-old.Time) / float64(span) * float64(time.Second))
+		if p.blockTime <= 0 {
+			p.blockTime = time.Second
+		}
+		Logger().Info("estimated block time", "block_time", p.blockTime, "blocks", span)
+	}
+
+	now := time.Now()
+	p.nextBlock = time.Unix(int64(latest.Time), 0).Add(p.blockTime)
+	if p.nextBlock.Before(now) || p.nextBlock.After(now.Add(p.blockTime)) {
+		p.nextBlock = now.Add(p.blockTime)
+	}
+	return nil
+}
+
+func (p *BlockTimePoll) Delay(failedPolls int) time.Duration {
+	if wait := time.Until(p.nextBlock); failedPolls == 1 && wait > 0 {
+		return wait
+	}
+	return max(p.blockTime/10, time.Millisecond)
+}
+
+// ParsePollStrategy parses a strategy spec:
+//
+//	fixed:<interval>
+//	backoff:<initial>[:<factor>[:<max>]]
+//	jitter:<interval>[:<fraction>]
+//	blocktime
+func ParsePollStrategy(spec string) (PollStrategy, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	args := parts[1:]
+	duration := func(i int, def time.Duration) (time.Duration, error) {
+		if i >= len(args) {
+			return def, nil
+		}
+		return time.ParseDuration(args[i])
+	}
+	float := func(i int, def float64) (float64, error) {
+		if i >= len(args) {
+			return def, nil
+		}
+		return strconv.ParseFloat(args[i], 64)
+	}
+
+	switch parts[0] {
+	case "fixed":
+		interval, err := duration(0, 10*time.Millisecond)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll strategy %q: %w", spec, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid poll strategy %q: interval must be positive", spec)
+		}
+		return FixedPoll{Interval: interval}, nil
+	case "backoff":
+		initial, err := duration(0, 5*time.Millisecond)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll strategy %q: %w", spec, err)
+		}
+		factor, err := float(1, 2)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll strategy %q: %w", spec, err)
+		}
+		maxDelay, err := duration(2, time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll strategy %q: %w", spec, err)
+		}
+		switch {
+		case initial <= 0:
+			return nil, fmt.Errorf("invalid poll strategy %q: initial delay must be positive", spec)
+		case !(factor >= 1): // also rejects NaN
+			return nil, fmt.Errorf("invalid poll strategy %q: backoff factor must be at least 1", spec)
+		case maxDelay < initial:
+			return nil, fmt.Errorf("invalid poll strategy %q: max delay must not be below the initial delay", spec)
+		}
+		return BackoffPoll{Initial: initial, Factor: factor, Max: maxDelay}, nil
+	case "jitter":
+		interval, err := duration(0, 50*time.Millisecond)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll strategy %q: %w", spec, err)
+		}
+		jitter, err := float(1, 0.5)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll strategy %q: %w", spec, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid poll strategy %q: interval must be positive", spec)
+		}
+		if !(jitter >= 0 && jitter < 1) {
+			return nil, fmt.Errorf("invalid poll strategy %q: jitter fraction must be in [0, 1)", spec)
+		}
+		return JitterPoll{Interval: interval, Jitter: jitter}, nil
+	case "blocktime":
+		return &BlockTimePoll{}, nil
+	default:
+		return nil, fmt.Errorf("unknown poll strategy: %s", spec)
+	}
+}
+
+// PollingResult is the outcome of confirming one transaction with one polling strategy.
+type PollingResult struct {
+	Strategy    string
+	TxIndex     int
+	TxHash      string
+	ConfirmTime int64 // milliseconds from the send returning until the receipt was seen
+	Calls       int   // eth_getTransactionReceipt calls made, including the successful one
+}
+
+// RunPollingComparison sends txCount transactions, cycling through strategies in a shuffled order every
+// round so they see comparable chain conditions, and confirms each with its strategy.
+func RunPollingComparison(txCount int, strategies []PollStrategy) ([]PollingResult, error) {
+	if len(strategies) == 0 {
+		return nil, fmt.Errorf("no polling strategies given")
+	}
+	client, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	chainID, err := getChainID(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	signer := signers[0]
+	fromAddress := signer.Address()
+
+	nonce, err := client.PendingNonceAt(ctx, fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	order := make([]int, len(strategies))
+	for i := range order {
+		order[i] = i
+	}
+
+	results := make([]PollingResult, 0, txCount)
+	for i := 0; i < txCount; i++ {
+		if i%len(order) == 0 {
+			rand.Shuffle(len(order), func(a, b int) { order[a], order[b] = order[b], order[a] })
+		}
+		strategy := strategies[order[i%len(order)]]
+
+		signedTx, err := signSelfTransfer(ctx, client, chainID, signer, nonce)
+		if err != nil {
+			return nil, err
+		}
+		if err := strategy.Prepare(ctx, client); err != nil {
+			return nil, err
+		}
+
+		if err := client.SendTransaction(ctx, signedTx); err != nil {
+			return nil, fmt.Errorf("failed to send transaction: %w", err)
+		}
+		txHash := signedTx.Hash()
+
+		confirmStart := time.Now()
+		_, failedPolls, err := pollReceiptWith(ctx, client, i+1, txHash, strategy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get receipt: %w", err)
+		}
+		result := PollingResult{
+			Strategy:    strategy.Name(),
+			TxIndex:     i + 1,
+			TxHash:      txHash.Hex(),
+			ConfirmTime: time.Since(confirmStart).Milliseconds(),
+			Calls:       failedPolls + 1,
+		}
+		Logger().Info("receipt confirmed", "tx", i+1, "strategy", result.Strategy,
+			"confirm_ms", result.ConfirmTime, "calls", result.Calls)
+
+		results = append(results, result)
+		nonce++
+	}
+
+	return results, nil
+}
+
+// PollingSummary aggregates the results of one strategy.
+type PollingSummary struct {
+	Strategy      string
+	Txs           int
+	MedianConfirm int64
+	P90Confirm    int64
+	AvgCalls      float64
+	Pareto        bool // no other strategy is both faster and cheaper
+}
+
+// SummarizePolling aggregates results per strategy, in order of first appearance, and marks the Pareto front
+// of median detection latency against RPC calls.
+func SummarizePolling(results []PollingResult) []PollingSummary {
+	var names []string
+	byStrategy := make(map[string][]PollingResult)
+	for _, r := range results {
+		if _, ok := byStrategy[r.Strategy]; !ok {
+			names = append(names, r.Strategy)
+		}
+		byStrategy[r.Strategy] = append(byStrategy[r.Strategy], r)
+	}
+
+	summaries := make([]PollingSummary, 0, len(names))
+	for _, name := range names {
+		rs := byStrategy[name]
+		confirms := make([]int64, len(rs))
+		calls := 0
+		for i, r := range rs {
+			confirms[i] = r.ConfirmTime
+			calls += r.Calls
+		}
+		sort.Slice(confirms, func(i, j int) bool { return confirms[i] < confirms[j] })
+		summaries = append(summaries, PollingSummary{
+			Strategy:      name,
+			Txs:           len(rs),
+			MedianConfirm: median(confirms),
+			P90Confirm:    percentile(confirms, 90),
+			AvgCalls:      float64(calls) / float64(len(rs)),
+		})
+	}
+
+	for i := range summaries {
+		summaries[i].Pareto = true
+		for j := range summaries {
+			a, b := summaries[i], summaries[j]
+			dominated := b.MedianConfirm <= a.MedianConfirm && b.AvgCalls <= a.AvgCalls &&
+				(b.MedianConfirm < a.MedianConfirm || b.AvgCalls < a.AvgCalls)
+			if i != j && dominated {
+				summaries[i].Pareto = false
+				break
+			}
+		}
+	}
+	return summaries
+}
+
+// PrintPollingReport prints detection latency against RPC calls spent for each strategy.
+func PrintPollingReport(summaries []PollingSummary) {
+	fmt.Println("\nPOLLING STRATEGY COMPARISON:")
+	fmt.Printf("%-28s %-6s %-14s %-14s %-12s %-7s\n", "STRATEGY", "TXS", "MEDIAN (ms)", "P90 (ms)", "CALLS/TX", "PARETO")
+	fmt.Println("-----------------------------------------------------------------------------------")
+	for _, s := range summaries {
+		pareto := ""
+		if s.Pareto {
+			pareto = "*"
+		}
+		fmt.Printf("%-28s %-6d %-14d %-14d %-12.1f %-7s\n", s.Strategy, s.Txs, s.MedianConfirm, s.P90Confirm, s.AvgCalls, pareto)
+	}
+}
@@ -0,0 +1,37 @@
+package bench
+
+import "testing"
+
+func TestParsePollStrategy(t *testing.T) {
+	tests := []struct {
+		in  string
+		err bool
+	}{
+		{in: "fixed"},
+		{in: "fixed:20ms"},
+		{in: "backoff:5ms:1.5:500ms"},
+		{in: "backoff:5ms:1:5ms"},
+		{in: "jitter:50ms:0"},
+		{in: "blocktime"},
+		{in: "fixed:0s", err: true},
+		{in: "fixed:-10ms", err: true},
+		{in: "backoff:0s", err: true},
+		{in: "backoff:5ms:0.5", err: true},
+		{in: "backoff:5ms:NaN", err: true},
+		{in: "backoff:50ms:2:10ms", err: true},
+		{in: "jitter:0s", err: true},
+		{in: "jitter:50ms:1", err: true},
+		{in: "jitter:50ms:-0.1", err: true},
+		{in: "jitter:50ms:NaN", err: true},
+		{in: "poll", err: true},
+	}
+	for _, tt := range tests {
+		_, err := ParsePollStrategy(tt.in)
+		if tt.err && err == nil {
+			t.Errorf("%q: expected an error", tt.in)
+		}
+		if !tt.err && err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+		}
+	}
+}
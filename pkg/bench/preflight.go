@@ -0,0 +1,128 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// mainnetChainIDs are production networks where benchmark transactions cost real money. Runs against them
+// must be allowed explicitly with ALLOWED_MAINNETS.
+var mainnetChainIDs = map[uint64]string{
+	1:       "Ethereum",
+	10:      "OP Mainnet",
+	56:      "BNB Smart Chain",
+	100:     "Gnosis",
+	130:     "Unichain",
+	137:     "Polygon",
+	250:     "Fantom",
+	324:     "zkSync Era",
+	480:     "World Chain",
+	1101:    "Polygon zkEVM",
+	5000:    "Mantle",
+	8453:    "Base",
+	34443:   "Mode",
+	42161:   "Arbitrum One",
+	42170:   "Arbitrum Nova",
+	43114:   "Avalanche C-Chain",
+	59144:   "Linea",
+	81457:   "Blast",
+	534352:  "Scroll",
+	7777777: "Zora",
+}
+
+var (
+	expectedChainID *big.Int
+	allowedMainnets []uint64
+	dryRun          bool
+)
+
+// SetDryRun makes subsequent runs build and sign every transaction and print the plan without sending.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// preflight verifies that it is safe to send txCount transactions from signer: the chain is the expected
+// one and not an unlisted mainnet, no transaction of the sender is pending, and the balance covers the
+// worst-case cost. extraTip is a priority fee per gas the transactions add to the fee cap, or nil. It
+// returns the nonce to start from.
+func preflight(ctx context.Context, client *ethclient.Client, chainID *big.Int, signer Signer, txCount int, extraTip *big.Int) (uint64, error) {
+	if expectedChainID != nil && chainID.Cmp(expectedChainID) != 0 {
+		return 0, fmt.Errorf("preflight: endpoint chain ID %s does not match EXPECTED_CHAIN_ID %s", chainID, expectedChainID)
+	}
+	if name, ok := mainnetChainIDs[chainID.Uint64()]; chainID.IsUint64() && ok && !slices.Contains(allowedMainnets, chainID.Uint64()) {
+		return 0, fmt.Errorf("preflight: endpoint is %s mainnet (chain ID %s); add it to ALLOWED_MAINNETS to run against it", name, chainID)
+	}
+
+	from := signer.Address()
+	latest, err := client.NonceAt(ctx, from, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest nonce: %w", err)
+	}
+	pending, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	if pending != latest {
+		return 0, fmt.Errorf("preflight: %s has %d pending txs (latest nonce %d, pending nonce %d); wait for them or replace them first",
+			from.Hex(), pending-latest, latest, pending)
+	}
+
+	_, gasFeeCap, err := suggestFees(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+	if extraTip != nil {
+		gasFeeCap.Add(gasFeeCap, extraTip)
+	}
+	perTx := new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(selfTransferGas))
+	perTx.Add(perTx, selfTransferValue)
+	need := new(big.Int).Mul(perTx, big.NewInt(int64(txCount)))
+	balance, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if balance.Cmp(need) < 0 {
+		return 0, fmt.Errorf("preflight: %s has %s ETH, %d txs may cost up to %s ETH (excluding L1 data fees)",
+			from.Hex(), FormatEther(balance), txCount, FormatEther(need))
+	}
+
+	Logger().Info("preflight checks passed", "chain_id", chainID, "from", from.Hex(), "nonce", pending,
+		"balance_eth", FormatEther(balance), "max_cost_eth", FormatEther(need))
+	return pending, nil
+}
+
+// reportDryRunPlan prints the dry run plan, or logs it while an observer may be drawing on stdout.
+func reportDryRunPlan(mode string, txs []*types.Transaction) {
+	if !observed() {
+		PrintDryRunPlan(mode, txs)
+		return
+	}
+	total := new(big.Int)
+	for i, tx := range txs {
+		maxFee := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+		total.Add(total, maxFee)
+		Logger().Info("dry run tx", "mode", mode, "tx", i+1, "nonce", tx.Nonce(), "gas", tx.Gas(),
+			"max_fee_eth", FormatEther(maxFee), "hash", tx.Hash().Hex())
+	}
+	Logger().Info("dry run complete, nothing sent", "mode", mode, "txs", len(txs),
+		"max_total_fee_eth", FormatEther(total))
+}
+
+// PrintDryRunPlan prints the transactions a dry run built and signed.
+func PrintDryRunPlan(mode string, txs []*types.Transaction) {
+	fmt.Printf("\nDRY RUN (%s): %d txs signed, nothing sent\n", mode, len(txs))
+	fmt.Printf("%-5s %-8s %-10s %-24s %s\n", "TX#", "NONCE", "GAS", "MAX FEE (ETH)", "HASH")
+	fmt.Println("------------------------------------------------------------------------------------------------------------")
+	total := new(big.Int)
+	for i, tx := range txs {
+		maxFee := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+		total.Add(total, maxFee)
+		fmt.Printf("%-5d %-8d %-10d %-24s %s\n", i+1, tx.Nonce(), tx.Gas(), FormatEther(maxFee), tx.Hash().Hex())
+	}
+	fmt.Printf("\nMax total fee: %s ETH (excluding L1 data fees)\n", FormatEther(total))
+}
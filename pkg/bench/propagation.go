@@ -0,0 +1,220 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// EndpointObservation records when one endpoint first exposed a transaction, measured from the start of its send.
+type EndpointObservation struct {
+	Endpoint    string
+	TxSeenTime  int64 // milliseconds until eth_getTransactionByHash returned the tx (pending or mined), -1 if never
+	ReceiptTime int64 // milliseconds until eth_getTransactionReceipt returned the receipt, -1 if never
+}
+
+type PropagationResult struct {
+	TxIndex      int
+	TxHash       string
+	SendTime     int64 // milliseconds
+	Observations []EndpointObservation
+}
+
+// RunPropagation sends txCount transactions through the configured RPC endpoint and, for each of them,
+// concurrently polls the sending endpoint and every endpoint in observers until they expose the
+// transaction and its receipt, or until timeout elapses.
+func RunPropagation(txCount int, observers []string, pollInterval, timeout time.Duration) ([]PropagationResult, error) {
+	sender, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
+	}
+	defer sender.Close()
+	ctx := context.Background()
+
+	// The sending endpoint is watched as well, as a reference for the others.
+	endpoints := append([]string{rpcEndpoint}, observers...)
+	clients := make([]*ethclient.Client, len(endpoints))
+	for i, endpoint := range endpoints {
+		clients[i], err = ethclient.Dial(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to RPC endpoint %s: %w", endpointLabel(endpoint), err)
+		}
+		defer clients[i].Close()
+	}
+
+	chainID, err := getChainID(ctx, sender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	signer := signers[0]
+	fromAddress := signer.Address()
+
+	nonce, err := sender.PendingNonceAt(ctx, fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	results := make([]PropagationResult, 0, txCount)
+	for i := 0; i < txCount; i++ {
+		txCtx, txSpan := startTxSpan(ctx, "propagation", i+1, nonce)
+
+		signedTx, err := signSelfTransfer(txCtx, sender, chainID, signer, nonce)
+		if err != nil {
+			endSpan(txSpan, err)
+			return nil, err
+		}
+		txHash := signedTx.Hash()
+		txSpan.SetAttributes(attribute.String("tx.hash", txHash.Hex()))
+
+		watchCtx, cancel := context.WithTimeout(txCtx, timeout)
+		observations := make([]EndpointObservation, len(clients))
+		var wg sync.WaitGroup
+
+		sendStart := time.Now()
+		for j, client := range clients {
+			observations[j] = EndpointObservation{Endpoint: endpointLabel(endpoints[j]), TxSeenTime: -1, ReceiptTime: -1}
+			obs := &observations[j]
+
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				if err := pollTxSeen(watchCtx, client, txHash, pollInterval); err == nil {
+					obs.TxSeenTime = time.Since(sendStart).Milliseconds()
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				if _, _, err := pollReceipt(watchCtx, client, i+1, txHash, pollInterval); err == nil {
+					obs.ReceiptTime = time.Since(sendStart).Milliseconds()
+				}
+			}()
+		}
+
+		sendCtx, sendSpan := tracer.Start(txCtx, "send_transaction")
+		err = sender.SendTransaction(sendCtx, signedTx)
+		sendDuration := time.Since(sendStart)
+		endSpan(sendSpan, err)
+		if err != nil {
+			cancel()
+			wg.Wait()
+			endSpan(txSpan, err)
+			return nil, fmt.Errorf("failed to send transaction: %w", err)
+		}
+		Logger().Info("tx sent", "tx", i+1, "hash", txHash.Hex(), "send_time", sendDuration)
+
+		wg.Wait()
+		cancel()
+		txSpan.End()
+
+		for _, obs := range observations {
+			Logger().Info("propagation observed", "tx", i+1, "endpoint", obs.Endpoint,
+				"tx_seen_ms", obs.TxSeenTime, "receipt_ms", obs.ReceiptTime)
+		}
+
+		results = append(results, PropagationResult{
+			TxIndex:      i + 1,
+			TxHash:       txHash.Hex(),
+			SendTime:     sendDuration.Milliseconds(),
+			Observations: observations,
+		})
+		nonce++
+	}
+
+	return results, nil
+}
+
+// pollTxSeen queries client for txHash every pollInterval until the node knows the transaction, pending or mined.
+func pollTxSeen(ctx context.Context, client *ethclient.Client, txHash common.Hash, pollInterval time.Duration) error {
+	for {
+		tx, _, err := client.TransactionByHash(ctx, txHash)
+		if err == nil && tx != nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// endpointLabel returns the host of an RPC URL so that API keys in the path or query are not printed.
+func endpointLabel(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return u.Host
+}
+
+// PrintPropagationReport prints, for every watched endpoint, how quickly it exposed the sent transactions.
+func PrintPropagationReport(results []PropagationResult) {
+	if len(results) == 0 {
+		Logger().Warn("no results to report")
+		return
+	}
+
+	numEndpoints := len(results[0].Observations)
+	firstReceipt := make([]int, numEndpoints)
+	for _, r := range results {
+		best := -1
+		for j, obs := range r.Observations {
+			if obs.ReceiptTime >= 0 && (best < 0 || obs.ReceiptTime < r.Observations[best].ReceiptTime) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			firstReceipt[best]++
+		}
+	}
+
+	fmt.Println("\nPROPAGATION STATISTICS (ms since send start):")
+	fmt.Printf("%-32s %-8s %-10s %-10s %-8s %-10s %-10s %-8s\n",
+		"ENDPOINT", "TX SEEN", "MED TX", "P90 TX", "RECEIPT", "MED RCPT", "P90 RCPT", "FIRST")
+	fmt.Println("------------------------------------------------------------------------------------------------------------")
+
+	for j := 0; j < numEndpoints; j++ {
+		var seen, receipts []int64
+		for _, r := range results {
+			obs := r.Observations[j]
+			if obs.TxSeenTime >= 0 {
+				seen = append(seen, obs.TxSeenTime)
+			}
+			if obs.ReceiptTime >= 0 {
+				receipts = append(receipts, obs.ReceiptTime)
+			}
+		}
+		sort.Slice(seen, func(a, b int) bool { return seen[a] < seen[b] })
+		sort.Slice(receipts, func(a, b int) bool { return receipts[a] < receipts[b] })
+
+		label := results[0].Observations[j].Endpoint
+		if j == 0 {
+			label += " (sender)"
+		}
+		fmt.Printf("%-32s %-8s %-10s %-10s %-8s %-10s %-10s %-8d\n",
+			label,
+			fmt.Sprintf("%d/%d", len(seen), len(results)),
+			formatPercentile(seen, 50), formatPercentile(seen, 90),
+			fmt.Sprintf("%d/%d", len(receipts), len(results)),
+			formatPercentile(receipts, 50), formatPercentile(receipts, 90),
+			firstReceipt[j],
+		)
+	}
+}
+
+// formatPercentile formats the p-th percentile of sorted, or "-" if it is empty.
+func formatPercentile(sorted []int64, p float64) string {
+	if len(sorted) == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", percentile(sorted, p))
+}
@@ -0,0 +1,238 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	reorgDepth        uint64
+	reorgPollInterval time.Duration
+	reorgTimeout      time.Duration
+)
+
+// SetReorgMonitoring makes subsequent async runs re-verify the inclusion block of every confirmed
+// transaction, checking every pollInterval until the block is depth blocks deep. After the last send the
+// run waits up to timeout for outstanding transactions. A zero depth disables it.
+func SetReorgMonitoring(depth uint64, pollInterval, timeout time.Duration) {
+	reorgDepth = depth
+	reorgPollInterval = pollInterval
+	reorgTimeout = timeout
+}
+
+// ReorgInfo describes what happened to a transaction's inclusion block after confirmation.
+type ReorgInfo struct {
+	Reorgs int // times the inclusion block was replaced while monitored
+	// Depth is the most confirmations the transaction had when its block was replaced.
+	Depth     uint64
+	Dropped   bool   // the transaction was no longer included anywhere after a reorg
+	Block     uint64 // inclusion block at the end of monitoring
+	BlockHash string
+}
+
+// reorgTx is a confirmed transaction whose inclusion block is being re-verified.
+type reorgTx struct {
+	resultIdx int
+	txIndex   int
+	txHash    common.Hash
+	info      ReorgInfo
+}
+
+// reorgMonitor re-verifies inclusion blocks in the background for the transactions added to it.
+type reorgMonitor struct {
+	client       *ethclient.Client
+	depth        uint64
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	pending []*reorgTx
+	infos   map[int]ReorgInfo // by result index
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startReorgMonitor(ctx context.Context, client *ethclient.Client, depth uint64, pollInterval time.Duration) *reorgMonitor {
+	ctx, cancel := context.WithCancel(ctx)
+	m := &reorgMonitor{
+		client:       client,
+		depth:        depth,
+		pollInterval: pollInterval,
+		infos:        make(map[int]ReorgInfo),
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+	go m.run(ctx)
+	return m
+}
+
+// add starts monitoring the transaction of results[resultIdx], confirmed in block with blockHash.
+func (m *reorgMonitor) add(resultIdx, txIndex int, txHash common.Hash, block uint64, blockHash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending = append(m.pending, &reorgTx{
+		resultIdx: resultIdx,
+		txIndex:   txIndex,
+		txHash:    txHash,
+		info:      ReorgInfo{Block: block, BlockHash: blockHash.Hex()},
+	})
+}
+
+func (m *reorgMonitor) outstanding() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending)
+}
+
+func (m *reorgMonitor) run(ctx context.Context) {
+	defer close(m.done)
+	var lastHead uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.pollInterval):
+		}
+
+		head, err := m.client.BlockNumber(ctx)
+		if err != nil || head == lastHead {
+			continue
+		}
+		lastHead = head
+
+		m.mu.Lock()
+		pending := append([]*reorgTx(nil), m.pending...)
+		m.mu.Unlock()
+
+		var remaining []*reorgTx
+		for _, tx := range pending {
+			if err := m.verify(ctx, tx, head); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				Logger().Warn("failed to verify inclusion block", "tx", tx.txIndex, "err", err)
+				remaining = append(remaining, tx)
+				continue
+			}
+			if tx.info.Dropped || head >= tx.info.Block+m.depth {
+				m.mu.Lock()
+				m.infos[tx.resultIdx] = tx.info
+				m.mu.Unlock()
+				continue
+			}
+			remaining = append(remaining, tx)
+		}
+
+		m.mu.Lock()
+		// Keep transactions added while verifying.
+		m.pending = append(remaining, m.pending[len(pending):]...)
+		m.mu.Unlock()
+	}
+}
+
+// verify checks that tx is still in the block it was confirmed in, and follows it if it moved.
+func (m *reorgMonitor) verify(ctx context.Context, tx *reorgTx, head uint64) error {
+	header, err := m.client.HeaderByNumber(ctx, new(big.Int).SetUint64(tx.info.Block))
+	if err != nil {
+		return fmt.Errorf("failed to get header %d: %w", tx.info.Block, err)
+	}
+	if header.Hash().Hex() == tx.info.BlockHash {
+		return nil
+	}
+
+	confirmations := uint64(0)
+	if head >= tx.info.Block {
+		confirmations = head - tx.info.Block + 1
+	}
+	tx.info.Reorgs++
+	tx.info.Depth = max(tx.info.Depth, confirmations)
+
+	receipt, err := m.client.TransactionReceipt(ctx, tx.txHash)
+	switch {
+	case errors.Is(err, ethereum.NotFound):
+		tx.info.Dropped = true
+		Logger().Warn("reorg dropped transaction", "tx", tx.txIndex, "block", tx.info.Block, "confirmations", confirmations)
+	case err != nil:
+		return fmt.Errorf("failed to get receipt after reorg: %w", err)
+	default:
+		Logger().Warn("reorg moved transaction", "tx", tx.txIndex, "from_block", tx.info.Block,
+			"to_block", receipt.BlockNumber.Uint64(), "confirmations", confirmations)
+		tx.info.Block = receipt.BlockNumber.Uint64()
+		tx.info.BlockHash = receipt.BlockHash.Hex()
+	}
+	return nil
+}
+
+// wait blocks until every added transaction is depth blocks deep or timeout elapses, stops the monitor
+// and returns what happened to each transaction by result index.
+func (m *reorgMonitor) wait(timeout time.Duration) map[int]ReorgInfo {
+	if n := m.outstanding(); n > 0 {
+		Logger().Info("waiting for reorg monitoring depth", "txs", n, "depth", m.depth, "timeout", timeout)
+	}
+	deadline := time.Now().Add(timeout)
+	for m.outstanding() > 0 && time.Now().Before(deadline) {
+		time.Sleep(m.pollInterval)
+	}
+	if n := m.outstanding(); n > 0 {
+		Logger().Warn("transactions not monitored to full depth before timeout", "txs", n, "timeout", timeout)
+	}
+	m.stop()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, tx := range m.pending {
+		m.infos[tx.resultIdx] = tx.info
+	}
+	return m.infos
+}
+
+// stop ends monitoring without waiting for outstanding transactions.
+func (m *reorgMonitor) stop() {
+	m.cancel()
+	<-m.done
+}
+
+// PrintReorgReport prints how many confirmed transactions were affected by reorgs and how deep.
+func PrintReorgReport(results []Result) {
+	monitored, reorged, dropped := 0, 0, 0
+	var events int
+	var maxDepth uint64
+	for _, r := range results {
+		if r.Reorg == nil {
+			continue
+		}
+		monitored++
+		if r.Reorg.Reorgs > 0 {
+			reorged++
+			events += r.Reorg.Reorgs
+			maxDepth = max(maxDepth, r.Reorg.Depth)
+		}
+		if r.Reorg.Dropped {
+			dropped++
+		}
+	}
+	if monitored == 0 {
+		return
+	}
+
+	fmt.Printf("\nREORGS (monitored %d txs to %d blocks deep):\n", monitored, reorgDepth)
+	fmt.Printf("Reorged txs: %d (%d reorg events), dropped: %d, max depth: %d confirmations\n", reorged, events, dropped, maxDepth)
+	for _, r := range results {
+		if r.Reorg == nil || r.Reorg.Reorgs == 0 {
+			continue
+		}
+		status := fmt.Sprintf("moved to block %d", r.Reorg.Block)
+		if r.Reorg.Dropped {
+			status = "dropped"
+		}
+		fmt.Printf("  tx %-5d confirmed in block %-10d depth %-4d %s\n", r.TxIndex, r.BlockNumber, r.Reorg.Depth, status)
+	}
+}
@@ -3,14 +3,18 @@ package bench
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"os"
 	"sort"
 	"time"
 
+	"github.com/iancoleman/strcase"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/plotutil"
 	"gonum.org/v1/plot/vg"
-	"log"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
 )
 
 func median(durations []int64) int64 {
@@ -35,6 +39,21 @@ func medianInt64(data []int64) int64 {
 	return sorted[mid]
 }
 
+// percentile returns the p-th percentile (0-100) of an ascending sorted slice using the nearest-rank method.
+func percentile[T ~int64](sorted []T, p float64) T {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
 func truncateHash(h string) string {
 	if len(h) < 14 {
 		return h
@@ -44,7 +63,7 @@ func truncateHash(h string) string {
 
 func PrintReport(results []Result) {
 	if len(results) == 0 {
-		fmt.Println("No results to report")
+		Logger().Warn("no results to report")
 		return
 	}
 
@@ -52,8 +71,10 @@ func PrintReport(results []Result) {
 	sendTimes := make([]int64, len(results))
 	confirmTimes := make([]int64, len(results))
 	totalTimes := make([]int64, len(results))
+	signTimes := make([]int64, len(results))
 
 	for i, r := range results {
+		signTimes[i] = r.SignTime
 		sendTimes[i] = r.SendTime
 		confirmTimes[i] = r.ConfirmTime
 		totalTimes[i] = r.TotalTime
@@ -101,10 +122,14 @@ func PrintReport(results []Result) {
 	minSend, maxSend, avgSend, medSend := computeStats(sendTimes)
 	minConfirm, maxConfirm, avgConfirm, medConfirm := computeStats(confirmTimes)
 	minTotal, maxTotal, avgTotal, medTotal := computeStats(totalTimes)
+	minSign, maxSign, avgSign, medSign := computeStats(signTimes)
 
 	fmt.Printf("%-13s %-10d %-10d %-10d %-10d\n", "Send time:", minSend, maxSend, avgSend, medSend)
 	fmt.Printf("%-13s %-10d %-10d %-10d %-10d\n", "Confirm time:", minConfirm, maxConfirm, avgConfirm, medConfirm)
 	fmt.Printf("%-13s %-10d %-10d %-10d %-10d\n", "Total time:", minTotal, maxTotal, avgTotal, medTotal)
+	// Sign times are in microseconds.
+	fmt.Printf("%-13s %-10.3f %-10.3f %-10.3f %-10.3f\n", "Sign time:", float64(minSign)/1000, float64(maxSign)/1000,
+		float64(avgSign)/1000, float64(medSign)/1000)
 }
 
 // PlotMetrics generates PNG plots for send, confirm, and total times.
@@ -145,13 +170,13 @@ func PlotMetrics(results []Result, filenamePrefix string) error {
 	}
 
 	if err := savePlot("Send Time", filenamePrefix+"_send.png", sendPts); err != nil {
-		log.Printf("Failed to save send time plot: %v", err)
+		Logger().Error("failed to save send time plot", "err", err)
 	}
 	if err := savePlot("Confirm Time", filenamePrefix+"_confirm.png", confirmPts); err != nil {
-		log.Printf("Failed to save confirm time plot: %v", err)
+		Logger().Error("failed to save confirm time plot", "err", err)
 	}
 	if err := savePlot("Total Time", filenamePrefix+"_total.png", totalPts); err != nil {
-		log.Printf("Failed to save total time plot: %v", err)
+		Logger().Error("failed to save total time plot", "err", err)
 	}
 
 	return nil
@@ -341,3 +366,433 @@ func PlotWithBlockNumberBaseline(asyncResults, syncResults []Result, rpcTime tim
 	}
 	return nil
 }
+
+// PlotPollingPareto plots median detection latency against RPC calls per tx for each polling strategy,
+// connecting the Pareto-optimal strategies.
+func PlotPollingPareto(summaries []PollingSummary, filename string) error {
+	if len(summaries) == 0 {
+		return fmt.Errorf("no results to plot")
+	}
+
+	pts := make(plotter.XYs, len(summaries))
+	labels := make([]string, len(summaries))
+	var front plotter.XYs
+	for i, s := range summaries {
+		pts[i].X, pts[i].Y = s.AvgCalls, float64(s.MedianConfirm)
+		labels[i] = s.Strategy
+		if s.Pareto {
+			front = append(front, pts[i])
+		}
+	}
+	sort.Slice(front, func(i, j int) bool { return front[i].X < front[j].X })
+
+	p := plot.New()
+	p.Title.Text = "Receipt Polling: Latency vs Cost"
+	p.X.Label.Text = "eth_getTransactionReceipt calls per tx"
+	p.Y.Label.Text = "Median detection latency (ms)"
+	p.Legend.Top = true
+	p.Legend.Left = false
+	p.Add(plotter.NewGrid())
+
+	scatter, err := plotter.NewScatter(pts)
+	if err != nil {
+		return fmt.Errorf("failed to create scatter: %w", err)
+	}
+	scatter.GlyphStyle.Radius = vg.Points(4)
+	p.Add(scatter)
+	p.Legend.Add("Strategy", scatter)
+
+	if len(front) > 1 {
+		frontLine, err := plotter.NewLine(front)
+		if err != nil {
+			return fmt.Errorf("failed to create Pareto front: %w", err)
+		}
+		frontLine.Color = color.RGBA{R: 255, G: 0, B: 0, A: 255}
+		frontLine.Dashes = []vg.Length{vg.Points(5), vg.Points(5)}
+		p.Add(frontLine)
+		p.Legend.Add("Pareto front", frontLine)
+	}
+
+	pointLabels, err := plotter.NewLabels(plotter.XYLabels{XYs: pts, Labels: labels})
+	if err != nil {
+		return fmt.Errorf("failed to create labels: %w", err)
+	}
+	pointLabels.Offset.X = vg.Points(6)
+	p.Add(pointLabels)
+
+	if err := p.Save(10*vg.Inch, 6*vg.Inch, filename); err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	return nil
+}
+
+// PlotBlockIntervalHistogram plots the distribution of receive intervals between observed blocks.
+func PlotBlockIntervalHistogram(blocks []BlockObservation, filename string) error {
+	intervals := BlockIntervals(blocks)
+	if len(intervals) == 0 {
+		return fmt.Errorf("no block intervals to plot")
+	}
+
+	values := make(plotter.Values, len(intervals))
+	for i, iv := range intervals {
+		values[i] = float64(iv)
+	}
+
+	p := plot.New()
+	p.Title.Text = "Block Interval Distribution"
+	p.X.Label.Text = "Interval between blocks received (ms)"
+	p.Y.Label.Text = "Blocks"
+	p.Add(plotter.NewGrid())
+
+	bins := min(max(len(values)/2, 5), 50)
+	hist, err := plotter.NewHist(values, bins)
+	if err != nil {
+		return fmt.Errorf("failed to create histogram: %w", err)
+	}
+	hist.FillColor = color.RGBA{R: 0, G: 0, B: 255, A: 160}
+	p.Add(hist)
+
+	if err := p.Save(12*vg.Inch, 5*vg.Inch, filename); err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	return nil
+}
+
+// PlotConfirmationLevels plots the latency to each observed confirmation level as a separate series.
+func PlotConfirmationLevels(results []Result, title, filename string) error {
+	var series []interface{}
+	for _, level := range ConfirmationLevels {
+		var pts plotter.XYs
+		for _, r := range results {
+			if v, ok := r.Levels[level]; ok {
+				pts = append(pts, plotter.XY{X: float64(r.TxIndex), Y: float64(v)})
+			}
+		}
+		if len(pts) > 0 {
+			series = append(series, string(level), pts)
+		}
+	}
+	if len(series) == 0 {
+		return fmt.Errorf("no confirmation levels to plot")
+	}
+
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = "#Transactions"
+	p.Y.Label.Text = "Time since send (ms)"
+	p.Legend.Top = true
+	p.Legend.Left = false
+	p.Add(plotter.NewGrid())
+
+	if err := plotutil.AddLinePoints(p, series...); err != nil {
+		return fmt.Errorf("failed to add line points: %w", err)
+	}
+
+	if err := p.Save(12*vg.Inch, 5*vg.Inch, filename); err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	return nil
+}
+
+// legendSwatch is a filled square legend entry for plotters without a thumbnail of their own.
+type legendSwatch struct {
+	color color.Color
+}
+
+func (s legendSwatch) Thumbnail(c *draw.Canvas) {
+	c.FillPolygon(s.color, []vg.Point{
+		{X: c.Min.X, Y: c.Min.Y}, {X: c.Min.X, Y: c.Max.Y}, {X: c.Max.X, Y: c.Max.Y}, {X: c.Max.X, Y: c.Min.Y},
+	})
+}
+
+// PlotFeeBoxes plots send, confirm and total time distributions as box plots grouped by tip level.
+func PlotFeeBoxes(summaries []FeeSummary, filename string) error {
+	metrics := []struct {
+		name   string
+		values func(FeeSummary) []int64
+		color  color.Color
+	}{
+		{"Send time", func(s FeeSummary) []int64 { return s.Send }, color.RGBA{R: 0, G: 0, B: 255, A: 120}},
+		{"Confirm time", func(s FeeSummary) []int64 { return s.Confirm }, color.RGBA{R: 0, G: 160, B: 0, A: 120}},
+		{"Total time", func(s FeeSummary) []int64 { return s.Total }, color.RGBA{R: 255, G: 0, B: 0, A: 120}},
+	}
+
+	p := plot.New()
+	p.Title.Text = "Latency by Priority Fee Level"
+	p.X.Label.Text = "Tip level (multiple of suggested tip)"
+	p.Y.Label.Text = "Time (ms)"
+	p.Legend.Top = true
+	p.Legend.Left = false
+	p.Add(plotter.NewGrid())
+
+	width := vg.Points(16)
+	names := make([]string, len(summaries))
+	plotted := false
+	for i, s := range summaries {
+		names[i] = s.Level
+		for j, m := range metrics {
+			data := m.values(s)
+			if len(data) == 0 {
+				continue
+			}
+			values := make(plotter.Values, len(data))
+			for k, v := range data {
+				values[k] = float64(v)
+			}
+			box, err := plotter.NewBoxPlot(width, float64(i), values)
+			if err != nil {
+				return fmt.Errorf("failed to create box plot: %w", err)
+			}
+			box.Offset = vg.Length(j-len(metrics)/2) * (width + vg.Points(2))
+			box.FillColor = m.color
+			p.Add(box)
+			plotted = true
+		}
+	}
+	if !plotted {
+		return fmt.Errorf("no confirmed transactions to plot")
+	}
+	for _, m := range metrics {
+		p.Legend.Add(m.name, legendSwatch{color: m.color})
+	}
+	p.NominalX(names...)
+
+	if err := p.Save(12*vg.Inch, 5*vg.Inch, filename); err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	return nil
+}
+
+// saveTiles draws plots side by side into one PNG of the given size.
+func saveTiles(plots []*plot.Plot, width, height vg.Length, filename string) error {
+	img := vgimg.NewWith(vgimg.UseWH(width, height), vgimg.UseBackgroundColor(color.White))
+	dc := draw.New(img)
+	tiles := draw.Tiles{Rows: 1, Cols: len(plots), PadX: vg.Millimeter * 4, PadTop: vg.Millimeter * 2,
+		PadBottom: vg.Millimeter * 2, PadLeft: vg.Millimeter * 2, PadRight: vg.Millimeter * 2}
+	canvases := plot.Align([][]*plot.Plot{plots}, tiles, dc)
+	for i, p := range plots {
+		p.Draw(canvases[0][i])
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	defer f.Close()
+	if _, err := (vgimg.PngCanvas{Canvas: img}).WriteTo(f); err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	return nil
+}
+
+// PlotLatencyHistograms plots the distributions of send, confirm and total time side by side.
+func PlotLatencyHistograms(results []Result, title, filename string) error {
+	if len(results) == 0 {
+		return fmt.Errorf("no results to plot")
+	}
+
+	var plots []*plot.Plot
+	for _, s := range ResultsCDFSeries(results) {
+		values := make(plotter.Values, len(s.Values))
+		for i, v := range s.Values {
+			values[i] = float64(v)
+		}
+
+		p := plot.New()
+		p.Title.Text = title + ": " + s.Name
+		p.X.Label.Text = "Time (ms)"
+		p.Y.Label.Text = "Transactions"
+		p.Add(plotter.NewGrid())
+
+		bins := min(max(len(values)/2, 5), 50)
+		hist, err := plotter.NewHist(values, bins)
+		if err != nil {
+			return fmt.Errorf("failed to create histogram: %w", err)
+		}
+		hist.FillColor = plotutil.Color(len(plots))
+		p.Add(hist)
+		plots = append(plots, p)
+	}
+
+	return saveTiles(plots, 15*vg.Inch, 4*vg.Inch, filename)
+}
+
+// CDFSeries is one latency distribution drawn on a CDF plot.
+type CDFSeries struct {
+	Name   string
+	Values []int64 // milliseconds, in any order
+}
+
+// ResultsCDFSeries returns the send, confirm and total times of results as named series.
+func ResultsCDFSeries(results []Result) []CDFSeries {
+	series := make([]CDFSeries, len(LatencyMetrics))
+	for i, metric := range LatencyMetrics {
+		series[i] = CDFSeries{Name: strcase.ToCamel(string(metric)) + " Time", Values: MetricValues(results, metric)}
+	}
+	return series
+}
+
+// PlotCDF plots the empirical CDF of each series on one chart, for comparing metrics, modes or chains.
+// With logScale the latency axis is logarithmic; values below 1 ms are drawn at 1 ms.
+func PlotCDF(series []CDFSeries, title string, logScale bool, filename string) error {
+	type cdfLine struct {
+		name string
+		pts  plotter.XYs
+	}
+	var lines []cdfLine
+	for _, s := range series {
+		if len(s.Values) == 0 {
+			continue
+		}
+		sorted := make([]int64, len(s.Values))
+		copy(sorted, s.Values)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		pts := make(plotter.XYs, 0, len(sorted)+1)
+		for i, v := range sorted {
+			x := float64(v)
+			if logScale {
+				x = math.Max(x, 1)
+			}
+			if i == 0 {
+				pts = append(pts, plotter.XY{X: x, Y: 0})
+			}
+			pts = append(pts, plotter.XY{X: x, Y: float64(i+1) / float64(len(sorted))})
+		}
+		lines = append(lines, cdfLine{name: s.Name, pts: pts})
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("no results to plot")
+	}
+
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = "Time (ms)"
+	p.Y.Label.Text = "Fraction of transactions"
+	p.Y.Min, p.Y.Max = 0, 1
+	p.Legend.Top = false
+	p.Legend.Left = false
+	p.Add(plotter.NewGrid())
+	if logScale {
+		p.X.Scale = plot.LogScale{}
+		p.X.Tick.Marker = plot.LogTicks{Prec: -1}
+		p.X.Label.Text = "Time (ms, log scale)"
+	}
+
+	for i, l := range lines {
+		line, err := plotter.NewLine(l.pts)
+		if err != nil {
+			return fmt.Errorf("failed to create CDF line: %w", err)
+		}
+		line.StepStyle = plotter.PostStep
+		line.Color = plotutil.Color(i)
+		line.Width = vg.Points(1.5)
+		p.Add(line)
+		p.Legend.Add(l.name, line)
+	}
+
+	if err := p.Save(12*vg.Inch, 5*vg.Inch, filename); err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	return nil
+}
+
+// PlotLatencyViolins draws a violin of the estimated density of send, confirm and total time, with a box
+// plot of the same values inside it.
+func PlotLatencyViolins(results []Result, title, filename string) error {
+	if len(results) == 0 {
+		return fmt.Errorf("no results to plot")
+	}
+
+	p := plot.New()
+	p.Title.Text = title
+	p.Y.Label.Text = "Time (ms)"
+	p.Add(plotter.NewGrid())
+
+	series := ResultsCDFSeries(results)
+	names := make([]string, len(series))
+	for i, s := range series {
+		names[i] = s.Name
+		values := make(plotter.Values, len(s.Values))
+		for j, v := range s.Values {
+			values[j] = float64(v)
+		}
+
+		if outline := violinOutline(values, float64(i), 0.4); outline != nil {
+			violin, err := plotter.NewPolygon(outline)
+			if err != nil {
+				return fmt.Errorf("failed to create violin: %w", err)
+			}
+			violin.Color = plotutil.Color(i)
+			violin.LineStyle.Width = vg.Points(1)
+			p.Add(violin)
+		}
+
+		box, err := plotter.NewBoxPlot(vg.Points(12), float64(i), values)
+		if err != nil {
+			return fmt.Errorf("failed to create box plot: %w", err)
+		}
+		box.FillColor = color.White
+		p.Add(box)
+	}
+	p.NominalX(names...)
+
+	if err := p.Save(12*vg.Inch, 5*vg.Inch, filename); err != nil {
+		return fmt.Errorf("failed to save plot: %w", err)
+	}
+	return nil
+}
+
+// violinOutline returns the outline of a violin centred on x whose half-width, at most halfWidth, follows
+// a Gaussian kernel density estimate of values with Silverman's bandwidth. It returns nil when values have
+// no spread.
+func violinOutline(values plotter.Values, x, halfWidth float64) plotter.XYs {
+	sorted := append(plotter.Values(nil), values...)
+	sort.Float64s(sorted)
+	n := float64(len(sorted))
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if n < 2 || hi == lo {
+		return nil
+	}
+
+	var mean, sq float64
+	for _, v := range sorted {
+		mean += v
+	}
+	mean /= n
+	for _, v := range sorted {
+		sq += (v - mean) * (v - mean)
+	}
+	sd := math.Sqrt(sq / (n - 1))
+	iqr := (sorted[int(0.75*(n-1))] - sorted[int(0.25*(n-1))]) / 1.34
+	spread := sd
+	if iqr > 0 && iqr < spread {
+		spread = iqr
+	}
+	bandwidth := 0.9 * spread * math.Pow(n, -0.2)
+	if bandwidth <= 0 {
+		bandwidth = (hi - lo) / 10
+	}
+
+	const steps = 100
+	ys := make([]float64, steps+1)
+	density := make([]float64, steps+1)
+	peak := 0.0
+	for i := range ys {
+		ys[i] = lo + (hi-lo)*float64(i)/steps
+		for _, v := range sorted {
+			z := (ys[i] - v) / bandwidth
+			density[i] += math.Exp(-z * z / 2)
+		}
+		peak = math.Max(peak, density[i])
+	}
+
+	outline := make(plotter.XYs, 0, 2*len(ys))
+	for i := range ys {
+		outline = append(outline, plotter.XY{X: x + halfWidth*density[i]/peak, Y: ys[i]})
+	}
+	for i := len(ys) - 1; i >= 0; i-- {
+		outline = append(outline, plotter.XY{X: x - halfWidth*density[i]/peak, Y: ys[i]})
+	}
+	return outline
+}
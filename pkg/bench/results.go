@@ -0,0 +1,49 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RunFile is the JSON export of a benchmark run, read back by the diff and baseline commands.
+type RunFile struct {
+	Mode      string
+	Endpoint  string // RPC endpoint label, without credentials
+	StartedAt time.Time
+	Results   []Result
+}
+
+// NewRunFile describes a run of mode against the configured endpoint that started at startedAt.
+func NewRunFile(mode string, startedAt time.Time, results []Result) RunFile {
+	return RunFile{Mode: mode, Endpoint: endpointLabel(rpcEndpoint), StartedAt: startedAt, Results: results}
+}
+
+// SaveRunFile writes run to path as indented JSON.
+func SaveRunFile(path string, run RunFile) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
+
+// LoadRunFile reads a run written by SaveRunFile.
+func LoadRunFile(path string) (RunFile, error) {
+	var run RunFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return run, fmt.Errorf("failed to read results: %w", err)
+	}
+	if err := json.Unmarshal(data, &run); err != nil {
+		return run, fmt.Errorf("invalid results file %s: %w", path, err)
+	}
+	if len(run.Results) == 0 {
+		return run, fmt.Errorf("results file %s has no results", path)
+	}
+	return run, nil
+}
@@ -0,0 +1,208 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCCall describes a JSON-RPC method invocation to benchmark.
+type RPCCall struct {
+	Name   string // label used in reports; defaults to Method
+	Method string
+	Params []interface{}
+}
+
+// RPCStats summarises the latencies of repeated calls to one method.
+type RPCStats struct {
+	Name      string
+	Calls     int
+	Errors    int
+	LastError string
+	Latencies []time.Duration // successful calls, ascending
+	Elapsed   time.Duration   // wall-clock time of the whole run
+}
+
+func (s RPCStats) Min() time.Duration    { return s.Latencies[0] }
+func (s RPCStats) Max() time.Duration    { return s.Latencies[len(s.Latencies)-1] }
+func (s RPCStats) Median() time.Duration { return medianDuration(s.Latencies) }
+
+func (s RPCStats) Avg() time.Duration {
+	var total time.Duration
+	for _, l := range s.Latencies {
+		total += l
+	}
+	return total / time.Duration(len(s.Latencies))
+}
+
+// Percentile returns the p-th percentile (0-100) of the successful call latencies.
+func (s RPCStats) Percentile(p float64) time.Duration {
+	return percentile(s.Latencies, p)
+}
+
+// RunRPCBenchmark performs count calls of call spread over concurrency workers, each waiting interval
+// between its calls, and returns the observed latencies.
+func RunRPCBenchmark(ctx context.Context, client *rpc.Client, call RPCCall, count, concurrency int, interval time.Duration) RPCStats {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	name := call.Name
+	if name == "" {
+		name = call.Method
+	}
+
+	var (
+		mu        sync.Mutex
+		next      atomic.Int64
+		wg        sync.WaitGroup
+		latencies = make([]time.Duration, 0, count)
+		errCount  int
+		lastErr   string
+	)
+
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				n := next.Add(1)
+				if n > int64(count) {
+					return
+				}
+
+				var result json.RawMessage
+				callStart := time.Now()
+				err := client.CallContext(ctx, &result, call.Method, call.Params...)
+				elapsed := time.Since(callStart)
+
+				mu.Lock()
+				if err != nil {
+					errCount++
+					lastErr = err.Error()
+				} else {
+					latencies = append(latencies, elapsed)
+				}
+				mu.Unlock()
+
+				if err != nil {
+					Logger().Warn("RPC call failed", "method", call.Method, "call", n, "err", err)
+				} else {
+					Logger().Debug("RPC call", "method", call.Method, "call", n, "elapsed", elapsed)
+				}
+
+				if interval > 0 && n < int64(count) {
+					time.Sleep(interval)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return RPCStats{
+		Name:      name,
+		Calls:     count,
+		Errors:    errCount,
+		LastError: lastErr,
+		Latencies: latencies,
+		Elapsed:   time.Since(start),
+	}
+}
+
+// RPCPresetNames lists the built-in read methods in the order they are benchmarked.
+var RPCPresetNames = []string{
+	"eth_blockNumber",
+	"eth_getBalance",
+	"eth_getLogs",
+	"eth_estimateGas",
+	"eth_getBlockByNumber",
+	"eth_feeHistory",
+}
+
+// RPCPreset returns the built-in call for name. address is used where a method needs an account and
+// logBlocks is the size of the block range queried by eth_getLogs, ending at the latest block.
+func RPCPreset(ctx context.Context, client *rpc.Client, name string, address common.Address, logBlocks uint64) (RPCCall, error) {
+	switch name {
+	case "eth_blockNumber":
+		return RPCCall{Method: name}, nil
+	case "eth_getBalance":
+		return RPCCall{Method: name, Params: []interface{}{address, "latest"}}, nil
+	case "eth_getLogs":
+		if logBlocks < 1 {
+			return RPCCall{}, fmt.Errorf("eth_getLogs block range must be at least 1, got %d", logBlocks)
+		}
+		var latest hexutil.Uint64
+		if err := client.CallContext(ctx, &latest, "eth_blockNumber"); err != nil {
+			return RPCCall{}, fmt.Errorf("failed to get latest block: %w", err)
+		}
+		from := uint64(0)
+		if uint64(latest)+1 > logBlocks {
+			from = uint64(latest) + 1 - logBlocks
+		}
+		filter := map[string]interface{}{
+			"fromBlock": hexutil.Uint64(from),
+			"toBlock":   latest,
+		}
+		return RPCCall{
+			Name:   fmt.Sprintf("%s (%d blocks)", name, logBlocks),
+			Method: name,
+			Params: []interface{}{filter},
+		}, nil
+	case "eth_estimateGas":
+		msg := map[string]interface{}{
+			"from":  address,
+			"to":    address,
+			"value": (*hexutil.Big)(selfTransferValue),
+		}
+		return RPCCall{Method: name, Params: []interface{}{msg}}, nil
+	case "eth_getBlockByNumber":
+		return RPCCall{Name: name + " (full)", Method: name, Params: []interface{}{"latest", true}}, nil
+	case "eth_feeHistory":
+		return RPCCall{Method: name, Params: []interface{}{hexutil.Uint64(20), "latest", []float64{25, 50, 75}}}, nil
+	default:
+		return RPCCall{}, fmt.Errorf("unknown RPC preset: %s", name)
+	}
+}
+
+// PrintRPCReport prints latency percentiles for each benchmarked method.
+func PrintRPCReport(stats []RPCStats) {
+	fmt.Println("\nRPC LATENCY STATISTICS (ms):")
+	fmt.Printf("%-32s %-7s %-7s %-9s %-9s %-9s %-9s %-9s %-9s %-9s\n",
+		"METHOD", "CALLS", "ERRORS", "MIN", "AVG", "P50", "P90", "P99", "MAX", "QPS")
+	fmt.Println("--------------------------------------------------------------------------------------------------------------------------")
+
+	for _, s := range stats {
+		if len(s.Latencies) == 0 {
+			fmt.Printf("%-32s %-7d %-7d all calls failed: %s\n", s.Name, s.Calls, s.Errors, s.LastError)
+			continue
+		}
+		fmt.Printf("%-32s %-7d %-7d %-9s %-9s %-9s %-9s %-9s %-9s %-9.1f\n",
+			s.Name, s.Calls, s.Errors,
+			formatMs(s.Min()), formatMs(s.Avg()), formatMs(s.Percentile(50)), formatMs(s.Percentile(90)), formatMs(s.Percentile(99)), formatMs(s.Max()),
+			float64(len(s.Latencies))/s.Elapsed.Seconds(),
+		)
+	}
+}
+
+// formatMs formats d as milliseconds with microsecond precision.
+func formatMs(d time.Duration) string {
+	return fmt.Sprintf("%.2f", float64(d.Microseconds())/1000)
+}
+
+// medianDuration returns the median of an ascending sorted slice.
+func medianDuration(sorted []time.Duration) time.Duration {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,238 @@
+package bench
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/term"
+)
+
+// defaultHDPath is the base derivation path of mnemonic accounts; the account index is appended to it.
+const defaultHDPath = "m/44'/60'/0'/0"
+
+// Signer signs transactions on behalf of one account.
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// keySigner signs with a private key held in memory.
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func newKeySigner(key *ecdsa.PrivateKey) *keySigner {
+	return &keySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (s *keySigner) Address() common.Address { return s.address }
+
+func (s *keySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// remoteSigner signs through a Clef-compatible external signer using account_signTransaction.
+type remoteSigner struct {
+	url     string
+	address common.Address
+}
+
+func (s *remoteSigner) Address() common.Address { return s.address }
+
+func (s *remoteSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := map[string]interface{}{
+		"from":    s.address,
+		"to":      tx.To(),
+		"gas":     hexutil.Uint64(tx.Gas()),
+		"value":   (*hexutil.Big)(tx.Value()),
+		"nonce":   hexutil.Uint64(tx.Nonce()),
+		"data":    hexutil.Bytes(tx.Data()),
+		"chainId": (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.LegacyTxType {
+		args["gasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+	}
+
+	resultRaw, err := callRPC(s.url, "account_signTransaction", args)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer failed: %w", err)
+	}
+	var result struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(resultRaw, &result); err != nil {
+		return nil, fmt.Errorf("invalid remote signer response: %w", err)
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(result.Raw); err != nil {
+		return nil, fmt.Errorf("invalid transaction from remote signer: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from remote signer: %w", err)
+	}
+	if from != s.address {
+		return nil, fmt.Errorf("remote signer signed as %s, expected %s", from.Hex(), s.address.Hex())
+	}
+	return signedTx, nil
+}
+
+// loadSigners builds the signers from every key source configured in the environment:
+//
+//	PRIVATE_KEYS                                comma-separated hex private keys
+//	KEYSTORE_FILES, KEYSTORE_PASSWORD_FILE      comma-separated keystore JSON files or directories; the
+//	                                            passphrase is prompted for if no password file is given
+//	MNEMONIC, HD_PATH, MNEMONIC_ACCOUNTS        BIP-39 mnemonic, base derivation path and number of accounts
+//	MNEMONIC_PASSPHRASE                         optional BIP-39 passphrase
+//	REMOTE_SIGNER_URL, REMOTE_SIGNER_ACCOUNTS   Clef-compatible signer and the accounts to use, all of its
+//	                                            accounts if none are listed
+func loadSigners() ([]Signer, error) {
+	var out []Signer
+
+	for _, keyHex := range privKeys {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(keyHex, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key in PRIVATE_KEYS: %w", err)
+		}
+		out = append(out, newKeySigner(key))
+	}
+
+	if files := SplitList(os.Getenv("KEYSTORE_FILES")); len(files) > 0 {
+		keys, err := loadKeystore(files, os.Getenv("KEYSTORE_PASSWORD_FILE"))
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			out = append(out, newKeySigner(key))
+		}
+	}
+
+	if mnemonic := strings.TrimSpace(os.Getenv("MNEMONIC")); mnemonic != "" {
+		path := os.Getenv("HD_PATH")
+		if path == "" {
+			path = defaultHDPath
+		}
+		count := 1
+		if s := os.Getenv("MNEMONIC_ACCOUNTS"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid MNEMONIC_ACCOUNTS: %q", s)
+			}
+			count = n
+		}
+		keys, err := deriveMnemonicKeys(mnemonic, os.Getenv("MNEMONIC_PASSPHRASE"), path, count)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			out = append(out, newKeySigner(key))
+		}
+	}
+
+	if url := os.Getenv("REMOTE_SIGNER_URL"); url != "" {
+		remotes, err := loadRemoteSigners(url, SplitList(os.Getenv("REMOTE_SIGNER_ACCOUNTS")))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, remotes...)
+	}
+
+	return out, nil
+}
+
+// loadKeystore decrypts the keystore files, expanding directories to the files they contain.
+func loadKeystore(paths []string, passwordFile string) ([]*ecdsa.PrivateKey, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore directory: %w", err)
+		}
+		for _, e := range entries {
+			if !e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+
+	var passphrase string
+	if passwordFile != "" {
+		data, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore password file: %w", err)
+		}
+		passphrase = strings.TrimRight(string(data), "\r\n")
+	} else {
+		fmt.Fprint(os.Stderr, "Keystore passphrase: ")
+		data, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore passphrase: %w", err)
+		}
+		passphrase = string(data)
+	}
+
+	keys := make([]*ecdsa.PrivateKey, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore file: %w", err)
+		}
+		key, err := keystore.DecryptKey(data, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt keystore file %s: %w", file, err)
+		}
+		keys = append(keys, key.PrivateKey)
+	}
+	return keys, nil
+}
+
+// loadRemoteSigners returns a signer per account of the external signer at url, or per listed account.
+func loadRemoteSigners(url string, accounts []string) ([]Signer, error) {
+	if len(accounts) == 0 {
+		resultRaw, err := callRPC(url, "account_list")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list remote signer accounts: %w", err)
+		}
+		if err := json.Unmarshal(resultRaw, &accounts); err != nil {
+			return nil, fmt.Errorf("invalid account_list response: %w", err)
+		}
+		if len(accounts) == 0 {
+			return nil, fmt.Errorf("remote signer has no accounts")
+		}
+	}
+
+	out := make([]Signer, 0, len(accounts))
+	for _, account := range accounts {
+		if !common.IsHexAddress(account) {
+			return nil, fmt.Errorf("invalid remote signer account: %s", account)
+		}
+		out = append(out, &remoteSigner{url: url, address: common.HexToAddress(account)})
+	}
+	return out, nil
+}
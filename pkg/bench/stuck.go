@@ -0,0 +1,255 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// replacementBumpPercent is how much a replacement raises both fees. Geth and most clients require at
+// least 10% to accept a transaction with the same nonce.
+const replacementBumpPercent = 12
+
+// StuckAction is what to do with a transaction that is not confirmed within the stuck timeout.
+type StuckAction string
+
+const (
+	// StuckRebroadcast sends the same signed transaction again.
+	StuckRebroadcast StuckAction = "rebroadcast"
+	// StuckBump replaces the transaction with the same one paying higher fees.
+	StuckBump StuckAction = "bump"
+	// StuckCancel replaces the transaction with a zero-value self-transfer paying higher fees.
+	StuckCancel StuckAction = "cancel"
+)
+
+// Outcomes of a transaction recorded in Result.Outcome.
+const (
+	OutcomeIncluded  = "included"  // the original transaction was included
+	OutcomeReplaced  = "replaced"  // a fee-bumped replacement was included
+	OutcomeCancelled = "cancelled" // a cancellation was included
+)
+
+// ParseStuckAction parses a stuck transaction action name.
+func ParseStuckAction(s string) (StuckAction, error) {
+	switch a := StuckAction(s); a {
+	case StuckRebroadcast, StuckBump, StuckCancel:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown stuck tx action %q, must be 'rebroadcast', 'bump' or 'cancel'", s)
+	}
+}
+
+// StuckPolicy decides how the async runner handles transactions that do not confirm.
+type StuckPolicy struct {
+	Action      StuckAction
+	Timeout     time.Duration // zero disables the policy
+	MaxAttempts int           // actions taken before giving up on the transaction
+}
+
+var stuckPolicy StuckPolicy
+
+// SetStuckPolicy sets the policy applied by subsequent async runs to transactions without a receipt
+// after p.Timeout.
+func SetStuckPolicy(p StuckPolicy) {
+	stuckPolicy = p
+}
+
+// Replacement records an action taken on a stuck transaction.
+type Replacement struct {
+	Action StuckAction
+	TxHash string // transaction sent by the action
+	After  int64  // milliseconds from the original send start
+	Err    string `json:",omitempty"` // why sending failed, if it did
+}
+
+// confirmWithPolicy polls for the receipt of tx and, if the stuck policy is enabled, acts on it every time
+// the policy timeout elapses without a receipt. Replacements the spend guard refuses are not sent. The
+// transaction at its nonce that was included and its receipt are returned, with the total number of failed
+// polls.
+func confirmWithPolicy(ctx context.Context, client *ethclient.Client, txIndex int, tx *types.Transaction, signer Signer,
+	chainID *big.Int, pollInterval time.Duration, sendStart time.Time, guard *spendGuard) (*types.Transaction, *types.Receipt, int, []Replacement, string, error) {
+	if stuckPolicy.Timeout <= 0 {
+		receipt, polls, err := pollReceipt(ctx, client, txIndex, tx.Hash(), pollInterval)
+		return tx, receipt, polls, nil, OutcomeIncluded, err
+	}
+
+	sent := []*types.Transaction{tx}
+	cancels := make(map[common.Hash]bool)
+	var replacements []Replacement
+	totalPolls := 0
+	for attempt := 0; ; attempt++ {
+		pollCtx, cancel := context.WithTimeout(ctx, stuckPolicy.Timeout)
+		receipt, polls, err := pollAnyReceipt(pollCtx, client, txIndex, sent, pollInterval)
+		cancel()
+		totalPolls += polls
+		if err == nil {
+			included, outcome := tx, OutcomeIncluded
+			if receipt.TxHash != tx.Hash() {
+				outcome = OutcomeReplaced
+				if cancels[receipt.TxHash] {
+					outcome = OutcomeCancelled
+				}
+				for _, candidate := range sent {
+					if candidate.Hash() == receipt.TxHash {
+						included = candidate
+					}
+				}
+			}
+			return included, receipt, totalPolls, replacements, outcome, nil
+		}
+		if ctx.Err() != nil {
+			return nil, nil, totalPolls, replacements, "", ctx.Err()
+		}
+		if attempt == stuckPolicy.MaxAttempts {
+			return nil, nil, totalPolls, replacements, "", fmt.Errorf("tx %d still unconfirmed after %d %s attempts",
+				txIndex, attempt, stuckPolicy.Action)
+		}
+
+		latest := sent[len(sent)-1]
+		next, err := stuckReplacement(ctx, client, signer, chainID, latest, stuckPolicy.Action)
+		if err != nil {
+			return nil, nil, totalPolls, replacements, "", err
+		}
+		event := Replacement{Action: stuckPolicy.Action, TxHash: next.Hash().Hex(), After: time.Since(sendStart).Milliseconds()}
+		if err := guard.allow(next); err != nil {
+			// Keep waiting on the transactions already sent rather than risk exceeding the limit.
+			event.Err = err.Error()
+			Logger().Warn("spend limit refused stuck tx action", "tx", txIndex, "action", stuckPolicy.Action, "err", err)
+		} else if err := client.SendTransaction(ctx, next); err != nil && !isAlreadyKnown(err) {
+			// Most often the nonce was included meanwhile, which the next poll finds.
+			event.Err = err.Error()
+			Logger().Warn("failed to send stuck tx action", "tx", txIndex, "action", stuckPolicy.Action, "err", err)
+		} else if next.Hash() != latest.Hash() {
+			sent = append(sent, next)
+			if stuckPolicy.Action == StuckCancel {
+				cancels[next.Hash()] = true
+			}
+		}
+		replacements = append(replacements, event)
+		if event.Err == "" {
+			Logger().Warn("tx stuck, acted on it", "tx", txIndex, "action", stuckPolicy.Action, "attempt", attempt+1,
+				"hash", event.TxHash, "after_ms", event.After)
+		}
+	}
+}
+
+// stuckReplacement returns the transaction to send for action on the stuck transaction tx.
+func stuckReplacement(ctx context.Context, client *ethclient.Client, signer Signer, chainID *big.Int,
+	tx *types.Transaction, action StuckAction) (*types.Transaction, error) {
+	if action == StuckRebroadcast {
+		return tx, nil
+	}
+
+	gasTipCap, gasFeeCap, err := suggestFees(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	gasTipCap = bigMax(bumpFee(tx.GasTipCap()), gasTipCap)
+	gasFeeCap = bigMax(bumpFee(tx.GasFeeCap()), gasFeeCap)
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasFeeCap = gasTipCap
+	}
+
+	to := signer.Address()
+	value, gas, data := tx.Value(), tx.Gas(), tx.Data()
+	if action == StuckCancel {
+		// A cancellation is a plain zero-value self-transfer whatever the original tx carried.
+		value, gas, data = new(big.Int), selfTransferGas, nil
+	}
+
+	var replacement *types.Transaction
+	if tx.Type() == types.LegacyTxType {
+		replacement = types.NewTransaction(tx.Nonce(), to, value, gas, gasFeeCap, data)
+	} else {
+		replacement = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     tx.Nonce(),
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       gas,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		})
+	}
+	signedTx, err := signer.SignTx(ctx, replacement, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign replacement transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+replacementBumpPercent))
+	return bumped.Div(bumped, big.NewInt(100)).Add(bumped, big.NewInt(1))
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// pollAnyReceipt polls every pollInterval until one of txs has a receipt or ctx is done.
+func pollAnyReceipt(ctx context.Context, client *ethclient.Client, txIndex int, txs []*types.Transaction, pollInterval time.Duration) (*types.Receipt, int, error) {
+	if len(txs) == 1 {
+		return pollReceipt(ctx, client, txIndex, txs[0].Hash(), pollInterval)
+	}
+	pollCount := 0
+	for {
+		for _, tx := range txs {
+			receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+			if err == nil && receipt != nil {
+				return receipt, pollCount, nil
+			}
+			if err != nil && !errors.Is(err, ethereum.NotFound) && ctx.Err() == nil {
+				observer.PollFailed(txIndex, err)
+			}
+		}
+		pollCount++
+		Logger().Debug("polling receipts", "tx", txIndex, "candidates", len(txs), "attempt", pollCount)
+
+		select {
+		case <-ctx.Done():
+			return nil, pollCount, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// PrintStuckReport lists the transactions the stuck policy acted on and how they ended.
+func PrintStuckReport(results []Result) {
+	counts := make(map[string]int)
+	var acted []Result
+	for _, r := range results {
+		if len(r.Replacements) > 0 {
+			acted = append(acted, r)
+		}
+		counts[r.Outcome]++
+	}
+	if len(acted) == 0 {
+		return
+	}
+
+	fmt.Printf("\nSTUCK TXS: %d acted on; outcomes: %d included, %d replaced, %d cancelled\n",
+		len(acted), counts[OutcomeIncluded], counts[OutcomeReplaced], counts[OutcomeCancelled])
+	for _, r := range acted {
+		actions := make([]string, len(r.Replacements))
+		for i, rep := range r.Replacements {
+			actions[i] = fmt.Sprintf("%s@%dms", rep.Action, rep.After)
+			if rep.Err != "" {
+				actions[i] += " (failed)"
+			}
+		}
+		fmt.Printf("  tx %-5d %-10s %s\n", r.TxIndex, r.Outcome, strings.Join(actions, ", "))
+	}
+}
@@ -0,0 +1,98 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/LampardNguyen234/evm-latency-bench/pkg/bench"
+
+// tracer is resolved through the global provider, so spans are no-ops until InitTracing is called.
+var tracer = otel.Tracer(tracerName)
+
+// InitTracing installs a global tracer provider exporting to an OTLP/HTTP collector at otlpEndpoint
+// (e.g. "localhost:4318") and/or to traceFile as JSON lines. If both are empty tracing stays disabled.
+// The returned function flushes pending spans and must be called before exit.
+func InitTracing(ctx context.Context, otlpEndpoint, traceFile string) (func(context.Context) error, error) {
+	if otlpEndpoint == "" && traceFile == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName("evm-latency-bench"),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	var file *os.File
+
+	if otlpEndpoint != "" {
+		exporter, err := otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpoint(otlpEndpoint),
+			otlptracehttp.WithInsecure(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	if traceFile != "" {
+		file, err = os.Create(traceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+		return err
+	}, nil
+}
+
+// startTxSpan opens the root span covering the whole lifecycle of one benchmarked transaction.
+func startTxSpan(ctx context.Context, mode string, txIndex int, nonce uint64) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "tx",
+		trace.WithAttributes(
+			attribute.String("bench.mode", mode),
+			attribute.Int("tx.index", txIndex),
+			attribute.Int64("tx.nonce", int64(nonce)),
+		),
+	)
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,54 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const selfTransferGas = uint64(21000)
+
+// selfTransferValue is the amount every benchmark transaction sends back to its sender.
+var selfTransferValue = big.NewInt(1e10) // 0.00000000001 ETH
+
+// signSelfTransfer builds and signs an EIP-1559 self-transfer at nonce, paying the suggested tip
+// with twice the suggested gas price as fee cap, as the sync runner does.
+func signSelfTransfer(ctx context.Context, client *ethclient.Client, chainID *big.Int, signer Signer, nonce uint64) (*types.Transaction, error) {
+	gasTipCap, gasFeeCap, err := suggestFees(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	toAddress := signer.Address()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       selfTransferGas,
+		To:        &toAddress,
+		Value:     selfTransferValue,
+	})
+
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// suggestFees returns the suggested tip and twice the suggested gas price as fee cap, as the runners use.
+func suggestFees(ctx context.Context, client *ethclient.Client) (gasTipCap, gasFeeCap *big.Int, err error) {
+	gasTipCap, err = client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get gas tip cap: %w", err)
+	}
+	gasFeeCap, err = client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get gas fee cap: %w", err)
+	}
+	return gasTipCap, gasFeeCap.Mul(gasFeeCap, big.NewInt(2)), nil
+}
@@ -0,0 +1,43 @@
+package bench
+
+import (
+	"time"
+)
+
+var (
+	warmupTxs    int
+	settlePeriod time.Duration
+)
+
+// SetWarmup makes subsequent runs send count extra transactions first. Their results are returned with
+// Warmup set and left out of the statistics by MeasuredResults.
+func SetWarmup(count int) {
+	warmupTxs = max(count, 0)
+}
+
+// SetSettlePeriod makes subsequent runs wait d after connecting and the preflight checks, before the
+// first transaction is sent.
+func SetSettlePeriod(d time.Duration) {
+	settlePeriod = d
+}
+
+// settle waits for the settle period, if any.
+func settle() {
+	if settlePeriod <= 0 || dryRun {
+		return
+	}
+	Logger().Info("waiting for settle period", "duration", settlePeriod)
+	time.Sleep(settlePeriod)
+}
+
+// MeasuredResults returns the results the latency statistics are computed from: it leaves out warm-up
+// transactions and cancelled ones, whose timings are those of the cancellation.
+func MeasuredResults(results []Result) []Result {
+	measured := make([]Result, 0, len(results))
+	for _, r := range results {
+		if !r.Warmup && r.Outcome != OutcomeCancelled {
+			measured = append(measured, r)
+		}
+	}
+	return measured
+}